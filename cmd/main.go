@@ -12,11 +12,15 @@ package main
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/rss3-network/vsl-reconcile/config"
+	"github.com/rss3-network/vsl-reconcile/internal/rpc"
 	"github.com/rss3-network/vsl-reconcile/internal/safe"
+	"github.com/rss3-network/vsl-reconcile/pkg/dryrun"
 	"github.com/rss3-network/vsl-reconcile/pkg/server"
 	"github.com/rss3-network/vsl-reconcile/pkg/service/aggregator"
+	"github.com/rss3-network/vsl-reconcile/pkg/service/election"
 	"github.com/rss3-network/vsl-reconcile/pkg/service/heartbeat"
 	"github.com/rss3-network/vsl-reconcile/pkg/service/http"
 	"github.com/rss3-network/vsl-reconcile/pkg/service/label"
@@ -25,7 +29,8 @@ import (
 )
 
 var (
-	debug bool
+	debug  bool
+	dryRun bool
 )
 
 var rootCmd = &cobra.Command{
@@ -36,11 +41,33 @@ var rootCmd = &cobra.Command{
 			return err
 		}
 
+		client, err := rpc.NewClient(cfg.RPC)
+		if err != nil {
+			return fmt.Errorf("failed to initialize rpc client: %w", err)
+		}
+
+		// Sequencers are only started and stopped through sequencerClient
+		var sequencerClient rpc.API = client
+
+		if dryRun {
+			zap.L().Warn("dry-run mode, sequencers and pods will not be changed")
+			dryrun.Enable()
+
+			sequencerClient = rpc.NewDryRun(client)
+		}
+
+		heartbeatService := heartbeat.New(sequencerClient)
+
+		// Services acting on sequencers only run on the elected leader
+		electionService := election.New(
+			label.New(sequencerClient),
+			heartbeatService,
+		)
+
 		providerAggregator := aggregator.New(
 			cfg,
-			&http.Service{},
-			&label.Service{},
-			&heartbeat.Service{},
+			http.New(electionService, heartbeatService),
+			electionService,
 		)
 
 		routinesPool := safe.NewPool(context.Background())
@@ -55,6 +82,7 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug mode")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "plan sequencer and pod changes without performing them")
 
 	if debug {
 		zap.ReplaceGlobals(zap.Must(zap.NewDevelopment()))
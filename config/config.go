@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -10,25 +12,311 @@ const (
 	DefaultCheckInterval = "60s"
 	DefaultMaxBlockTime  = "5m"
 
-	EnvDiscoverySTS  = "DISCOVERY_STS"
-	EnvDiscoveryNS   = "DISCOVERY_NS"
-	EnvCheckInterval = "CHECK_INTERVAL"
-	EnvMaxBlockTime  = "MAX_BLOCK_TIME"
+	DefaultDiscoveryScheme   = "http"
+	DefaultDiscoveryPortName = "rpc"
+	DefaultDiscoveryPort     = 9545
+	DefaultClusterDomain     = "cluster.local"
+
+	DefaultExecutionPortName = "geth-rpc"
+
+	DefaultHandoffSyncTimeout = "10s"
+	DefaultSwitchoverCooldown = "1m"
+	DefaultMaxSwitchovers     = 6
+	DefaultQuarantine         = "10m"
+	DefaultBootstrapTimeout   = "5m"
+	DefaultDegradedBackoff    = "5s"
+	DefaultDegradedMaxBackoff = "1m"
+	DefaultSafeHeadStall      = "10m"
+
+	DefaultRPCTimeout    = "10s"
+	DefaultRPCRetries    = 2
+	DefaultRPCBackoff    = "1s"
+	DefaultRPCMaxBackoff = "10s"
+	DefaultJWTSecretKey  = "jwt.hex"
+
+	StateStoreFile      = "file"
+	StateStoreConfigMap = "configmap"
+	StateStoreNone      = "none"
+
+	DefaultStateStore     = StateStoreFile
+	DefaultStateFile      = "/app/data/state.json"
+	DefaultStateConfigMap = "vsl-reconcile-state"
+
+	DefaultLeaderElectionName          = "vsl-reconcile"
+	DefaultLeaderElectionLeaseDuration = "15s"
+	DefaultLeaderElectionRenewDeadline = "10s"
+	DefaultLeaderElectionRetryPeriod   = "2s"
+
+	EnvSequencersList         = "SEQUENCERS_LIST"
+	EnvDiscoverySTS           = "DISCOVERY_STS"
+	EnvDiscoveryNS            = "DISCOVERY_NS"
+	EnvDiscoveryScheme        = "DISCOVERY_SCHEME"
+	EnvDiscoveryPortName      = "DISCOVERY_PORT_NAME"
+	EnvDiscoveryPort          = "DISCOVERY_PORT"
+	EnvClusterDomain          = "CLUSTER_DOMAIN"
+	EnvExecutionPortName      = "EXECUTION_PORT_NAME"
+	EnvExecutionURL           = "EXECUTION_URL_TEMPLATE"
+	EnvCheckInterval          = "CHECK_INTERVAL"
+	EnvMaxBlockTime           = "MAX_BLOCK_TIME"
+	EnvAPIToken               = "API_TOKEN"
+	EnvMaintenance            = "MAINTENANCE_MODE"
+	EnvSequencerPriorities    = "SEQUENCER_PRIORITIES"
+	EnvFailback               = "FAILBACK"
+	EnvHandoffSyncTimeout     = "HANDOFF_SYNC_TIMEOUT"
+	EnvSwitchoverCooldown     = "SWITCHOVER_COOLDOWN"
+	EnvMaxSwitchovers         = "MAX_SWITCHOVERS_PER_HOUR"
+	EnvQuarantine             = "QUARANTINE_DURATION"
+	EnvBootstrapTimeout       = "BOOTSTRAP_TIMEOUT"
+	EnvDegradedBackoff        = "DEGRADED_BACKOFF"
+	EnvDegradedMaxBackoff     = "DEGRADED_MAX_BACKOFF"
+	EnvNotifyWebhookURL       = "NOTIFY_WEBHOOK_URL"
+	EnvSafeHeadStall          = "SAFE_HEAD_STALL_TIMEOUT"
+	EnvSafeHeadHold           = "SAFE_HEAD_HOLD_PROMOTION"
+	EnvRPCTimeout             = "RPC_TIMEOUT"
+	EnvRPCRetries             = "RPC_RETRIES"
+	EnvRPCBackoff             = "RPC_BACKOFF"
+	EnvRPCMaxBackoff          = "RPC_MAX_BACKOFF"
+	EnvRPCTLSCAFile           = "RPC_TLS_CA_FILE"
+	EnvRPCTLSCertFile         = "RPC_TLS_CERT_FILE"
+	EnvRPCTLSKeyFile          = "RPC_TLS_KEY_FILE"
+	EnvRPCTLSServerName       = "RPC_TLS_SERVER_NAME"
+	EnvJWTSecretFile          = "JWT_SECRET_FILE"
+	EnvJWTSecret              = "JWT_SECRET"
+	EnvJWTSecretKey           = "JWT_SECRET_KEY"
+	EnvExecutionJWTSecretFile = "EXECUTION_JWT_SECRET_FILE"
+	EnvStateStore             = "STATE_STORE"
+	EnvStateFile              = "STATE_FILE"
+	EnvStateConfigMap         = "STATE_CONFIGMAP"
+
+	HealthCheckL1Head       = "l1_head"
+	HealthCheckSafeLag      = "safe_lag"
+	HealthCheckFinalizedLag = "finalized_lag"
+	HealthCheckPeers        = "peers"
+	HealthCheckRPCLatency   = "rpc_latency"
+
+	HealthCheckExecution      = "execution"
+	HealthCheckExecutionPeers = "execution_peers"
+
+	HealthPolicyMin  = "min"
+	HealthPolicyMean = "mean"
+
+	// DefaultHealthChecks enables no check, so only stopped, stalled and unreachable primary sequencers, or those with a failing op-geth, are switched over
+	DefaultHealthChecks           = ""
+	DefaultHealthPolicy           = HealthPolicyMin
+	DefaultHealthDegradedScore    = 0.75
+	DefaultHealthUnhealthyScore   = 0.25
+	DefaultHealthL1HeadWarn       = "2m"
+	DefaultHealthL1HeadCrit       = "10m"
+	DefaultHealthSafeLagWarn      = 1800
+	DefaultHealthSafeLagCrit      = 7200
+	DefaultHealthFinalizedLagWarn = 3600
+	DefaultHealthFinalizedLagCrit = 14400
+	DefaultHealthPeersWarn        = 3
+	DefaultHealthPeersCrit        = 0
+	DefaultHealthRPCLatencyWarn   = "1s"
+	DefaultHealthRPCLatencyCrit   = "5s"
+
+	DefaultHealthExecutionLagWarn   = 10
+	DefaultHealthExecutionLagCrit   = 60
+	DefaultHealthExecutionPeersWarn = 1
+	DefaultHealthExecutionPeersCrit = 0
+
+	EnvHealthChecks           = "HEALTH_CHECKS"
+	EnvHealthPolicy           = "HEALTH_POLICY"
+	EnvHealthDegradedScore    = "HEALTH_DEGRADED_SCORE"
+	EnvHealthUnhealthyScore   = "HEALTH_UNHEALTHY_SCORE"
+	EnvHealthL1HeadWarn       = "HEALTH_L1_HEAD_WARN"
+	EnvHealthL1HeadCrit       = "HEALTH_L1_HEAD_CRIT"
+	EnvHealthSafeLagWarn      = "HEALTH_SAFE_LAG_WARN"
+	EnvHealthSafeLagCrit      = "HEALTH_SAFE_LAG_CRIT"
+	EnvHealthFinalizedLagWarn = "HEALTH_FINALIZED_LAG_WARN"
+	EnvHealthFinalizedLagCrit = "HEALTH_FINALIZED_LAG_CRIT"
+	EnvHealthPeersWarn        = "HEALTH_PEERS_WARN"
+	EnvHealthPeersCrit        = "HEALTH_PEERS_CRIT"
+	EnvHealthRPCLatencyWarn   = "HEALTH_RPC_LATENCY_WARN"
+	EnvHealthRPCLatencyCrit   = "HEALTH_RPC_LATENCY_CRIT"
+
+	EnvHealthExecutionLagWarn   = "HEALTH_EXECUTION_LAG_WARN"
+	EnvHealthExecutionLagCrit   = "HEALTH_EXECUTION_LAG_CRIT"
+	EnvHealthExecutionPeersWarn = "HEALTH_EXECUTION_PEERS_WARN"
+	EnvHealthExecutionPeersCrit = "HEALTH_EXECUTION_PEERS_CRIT"
+
+	EnvLeaderElection              = "LEADER_ELECTION"
+	EnvLeaderElectionName          = "LEADER_ELECTION_NAME"
+	EnvLeaderElectionNS            = "LEADER_ELECTION_NS"
+	EnvLeaderElectionLeaseDuration = "LEADER_ELECTION_LEASE_DURATION"
+	EnvLeaderElectionRenewDeadline = "LEADER_ELECTION_RENEW_DEADLINE"
+	EnvLeaderElectionRetryPeriod   = "LEADER_ELECTION_RETRY_PERIOD"
+	EnvPodName                     = "POD_NAME"
 )
 
 type Config struct {
-	DiscoverySTS string
-	DiscoveryNS  string
+	// SequencersList is a static list of sequencers, which are discovered from DiscoverySTS otherwise
+	SequencersList []string
+	DiscoverySTS   string
+	DiscoveryNS    string
+
+	// DiscoveryScheme, DiscoveryPortName, DiscoveryPort and ClusterDomain build the StatefulSet pod endpoints,
+	// the port named DiscoveryPortName in the pod template is used, DiscoveryPort if there is none
+	DiscoveryScheme   string
+	DiscoveryPortName string
+	DiscoveryPort     int
+	ClusterDomain     string
+
+	// ExecutionURL is a template of the op-geth endpoint paired with each sequencer, with {scheme} and {host} of the op-node endpoint.
+	// Otherwise the port named ExecutionPortName in the StatefulSet pod template is used, op-geth is not checked if there is none
+	ExecutionURL      string
+	ExecutionPortName string
 
 	CheckInterval time.Duration
 	MaxBlockTime  time.Duration
+
+	// SequencerPriorities maps sequencer pod names or endpoints to their priority, higher is preferred
+	SequencerPriorities map[string]int
+	// Failback hands block production back to a preferred sequencer once it is ready
+	Failback bool
+
+	// HandoffSyncTimeout is how long a candidate is waited for to sync the handed-off unsafe block before it is activated,
+	// 0 to only check it once
+	HandoffSyncTimeout time.Duration
+
+	// SwitchoverCooldown is how long automatic switchovers are deferred after a switchover,
+	// MaxSwitchovers is how many switchovers are allowed per hour, 0 for no limit, and
+	// Quarantine is how long a sequencer is only tried as last resort after it failed to activate
+	SwitchoverCooldown time.Duration
+	MaxSwitchovers     int
+	Quarantine         time.Duration
+
+	// BootstrapTimeout is how long bootstrap is retried before the heartbeat loop starts without a primary sequencer,
+	// 0 to retry forever
+	BootstrapTimeout time.Duration
+
+	// DegradedBackoff is the wait before retrying promotion when no sequencer could be activated,
+	// doubling up to DegradedMaxBackoff
+	DegradedBackoff    time.Duration
+	DegradedMaxBackoff time.Duration
+
+	// NotifyWebhookURL receives notable events as JSON, which are not sent if empty
+	NotifyWebhookURL string
+
+	// SafeHeadStallTimeout is how long the safe head may not advance before it is reported as stalled, 0 to disable,
+	// SafeHeadHoldPromotion defers automatic switchovers and promotions while it is stalled
+	SafeHeadStallTimeout  time.Duration
+	SafeHeadHoldPromotion bool
+
+	RPC RPC
+
+	Health Health
+
+	// Maintenance pauses automatic failover on startup
+	Maintenance bool
+
+	// StateStore persists the reconcile state across restarts, in StateFile or in StateConfigMap in DiscoveryNS
+	StateStore     string
+	StateFile      string
+	StateConfigMap string
+
+	// APIToken authenticates requests to the HTTP admin API, which is disabled if empty
+	APIToken string
+
+	LeaderElection LeaderElection
+}
+
+// RPC configures the JSON-RPC calls to sequencers.
+type RPC struct {
+	// Timeout is the deadline of a single request
+	Timeout time.Duration
+
+	// Retries is how many times a failed request is retried,
+	// waiting Backoff at first and doubling up to MaxBackoff in between
+	Retries    int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// JWTSecretFile or the JWTSecretKey of the Secret JWTSecret in JWTSecretNamespace holds the hex encoded secret
+	// signing the requests to every op-node sequencer, which are not authenticated if neither is set
+	JWTSecretFile      string
+	JWTSecret          string
+	JWTSecretKey       string
+	JWTSecretNamespace string
+
+	// ExecutionJWTSecretFile holds the secret signing the op-geth requests, which never use the op-node secret
+	ExecutionJWTSecretFile string
+
+	TLS TLS
+}
+
+// TLS configures the connections to sequencers with the https scheme.
+type TLS struct {
+	// CAFile is a PEM bundle verifying the sequencers, the system roots are used if empty
+	CAFile string
+
+	// CertFile and KeyFile are the PEM client certificate and key for mTLS
+	CertFile string
+	KeyFile  string
+
+	// ServerName overrides the name verified in the certificates of the sequencers, e.g. instead of the pod DNS names
+	ServerName string
+}
+
+// Health configures the health checks of sequencers.
+type Health struct {
+	// Checks are the names of the enabled health checks
+	Checks []string
+
+	// Policy aggregates the scores of the checks, which is degraded below DegradedScore and unhealthy below UnhealthyScore
+	Policy         string
+	DegradedScore  float64
+	UnhealthyScore float64
+
+	// L1HeadAge and RPCLatency are in seconds, SafeLag, FinalizedLag and ExecutionLag in blocks
+	L1HeadAge    Threshold
+	SafeLag      Threshold
+	FinalizedLag Threshold
+	Peers        Threshold
+	RPCLatency   Threshold
+
+	// ExecutionLag is how far op-geth is behind the unsafe head of op-node, ExecutionPeers are the peers of op-geth
+	ExecutionLag   Threshold
+	ExecutionPeers Threshold
+}
+
+// Threshold scores a health signal 1 up to Warn and 0 from Crit, linearly in between.
+// Crit is below Warn for signals where lower is worse, like connected peers.
+type Threshold struct {
+	Warn float64
+	Crit float64
+}
+
+// LeaderElection configures the Lease based leader election between reconcile replicas.
+type LeaderElection struct {
+	Enabled   bool
+	Name      string
+	Namespace string
+	Identity  string
+
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
 }
 
 func Setup() (*Config, error) {
 	// Read sequencers list from environment variable (comma-separated)
+	var sequencersList []string
+
+	for _, sequencer := range strings.Split(os.Getenv(EnvSequencersList), ",") {
+		if sequencer = strings.TrimSpace(sequencer); sequencer != "" {
+			sequencersList = append(sequencersList, sequencer)
+		}
+	}
+
 	discoverySTS := os.Getenv(EnvDiscoverySTS)
-	if discoverySTS == "" {
-		return nil, fmt.Errorf("statefulset name is not provided")
+
+	switch {
+	case len(sequencersList) == 0 && discoverySTS == "":
+		return nil, fmt.Errorf("neither sequencers list nor statefulset name is provided")
+	case len(sequencersList) > 0 && discoverySTS != "":
+		return nil, fmt.Errorf("only one of sequencers list and statefulset name can be provided")
 	}
 
 	discoveryNS := os.Getenv(EnvDiscoveryNS)
@@ -36,37 +324,557 @@ func Setup() (*Config, error) {
 		discoveryNS = "default"
 	}
 
-	// Parse check interval
-	checkIntervalStr := os.Getenv(EnvCheckInterval)
-	if checkIntervalStr == "" {
-		checkIntervalStr = DefaultCheckInterval
+	discoveryScheme := os.Getenv(EnvDiscoveryScheme)
+	if discoveryScheme == "" {
+		discoveryScheme = DefaultDiscoveryScheme
 	}
 
-	checkInterval, err := time.ParseDuration(checkIntervalStr)
+	discoveryPortName := os.Getenv(EnvDiscoveryPortName)
+	if discoveryPortName == "" {
+		discoveryPortName = DefaultDiscoveryPortName
+	}
+
+	discoveryPort, err := intFromEnv(EnvDiscoveryPort, DefaultDiscoveryPort)
+	if err != nil {
+		return nil, err
+	}
+
+	executionPortName := os.Getenv(EnvExecutionPortName)
+	if executionPortName == "" {
+		executionPortName = DefaultExecutionPortName
+	}
+
+	clusterDomain := os.Getenv(EnvClusterDomain)
+	if clusterDomain == "" {
+		clusterDomain = DefaultClusterDomain
+	}
+
+	// Parse check interval
+	checkInterval, err := durationFromEnv(EnvCheckInterval, DefaultCheckInterval)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse check interval str (%s): %w", checkIntervalStr, err)
+		return nil, err
 	}
 
 	// Parse max block time (before we consider the sequencer unhealthy)
-	maxBlockTimeStr := os.Getenv(EnvMaxBlockTime)
-	if maxBlockTimeStr == "" {
-		maxBlockTimeStr = DefaultMaxBlockTime
+	maxBlockTime, err := durationFromEnv(EnvMaxBlockTime, DefaultMaxBlockTime)
+	if err != nil {
+		return nil, err
 	}
 
-	maxBlockTime, err := time.ParseDuration(maxBlockTimeStr)
+	// Stalls are measured from the unsafe L2 head timestamp, so the max block time can be shorter than the check interval
+	if checkInterval <= 0 || maxBlockTime <= 0 {
+		return nil, fmt.Errorf("check interval (%s) and max block time (%s) must be positive", checkInterval, maxBlockTime)
+	}
+
+	sequencerPriorities, err := prioritiesFromEnv(EnvSequencerPriorities)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse max block time str (%s): %w", maxBlockTimeStr, err)
+		return nil, err
 	}
 
-	if maxBlockTime < checkInterval {
-		return nil, fmt.Errorf("max block time (%s) must be greater than check interval (%s)",
-			maxBlockTime, checkInterval)
+	failback, err := boolFromEnv(EnvFailback, false)
+	if err != nil {
+		return nil, err
+	}
+
+	handoffSyncTimeout, err := durationFromEnv(EnvHandoffSyncTimeout, DefaultHandoffSyncTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if handoffSyncTimeout < 0 {
+		return nil, fmt.Errorf("handoff sync timeout (%s) must not be negative", handoffSyncTimeout)
+	}
+
+	switchoverCooldown, err := durationFromEnv(EnvSwitchoverCooldown, DefaultSwitchoverCooldown)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSwitchovers, err := intFromEnv(EnvMaxSwitchovers, DefaultMaxSwitchovers)
+	if err != nil {
+		return nil, err
+	}
+
+	quarantine, err := durationFromEnv(EnvQuarantine, DefaultQuarantine)
+	if err != nil {
+		return nil, err
+	}
+
+	if switchoverCooldown < 0 || maxSwitchovers < 0 || quarantine < 0 {
+		return nil, fmt.Errorf("switchover cooldown (%s), max switchovers (%d) and quarantine (%s) must not be negative",
+			switchoverCooldown, maxSwitchovers, quarantine)
+	}
+
+	bootstrapTimeout, err := durationFromEnv(EnvBootstrapTimeout, DefaultBootstrapTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if bootstrapTimeout < 0 {
+		return nil, fmt.Errorf("bootstrap timeout (%s) must not be negative", bootstrapTimeout)
+	}
+
+	degradedBackoff, err := durationFromEnv(EnvDegradedBackoff, DefaultDegradedBackoff)
+	if err != nil {
+		return nil, err
+	}
+
+	degradedMaxBackoff, err := durationFromEnv(EnvDegradedMaxBackoff, DefaultDegradedMaxBackoff)
+	if err != nil {
+		return nil, err
+	}
+
+	if degradedBackoff <= 0 {
+		return nil, fmt.Errorf("degraded backoff (%s) must be positive", degradedBackoff)
+	}
+
+	if degradedMaxBackoff < degradedBackoff {
+		return nil, fmt.Errorf("degraded max backoff (%s) must not be less than backoff (%s)", degradedMaxBackoff, degradedBackoff)
+	}
+
+	safeHeadStallTimeout, err := durationFromEnv(EnvSafeHeadStall, DefaultSafeHeadStall)
+	if err != nil {
+		return nil, err
+	}
+
+	if safeHeadStallTimeout < 0 {
+		return nil, fmt.Errorf("safe head stall timeout (%s) must not be negative", safeHeadStallTimeout)
+	}
+
+	safeHeadHoldPromotion, err := boolFromEnv(EnvSafeHeadHold, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rpc, err := setupRPC(discoveryNS)
+	if err != nil {
+		return nil, err
+	}
+
+	health, err := setupHealth()
+	if err != nil {
+		return nil, err
+	}
+
+	maintenance, err := boolFromEnv(EnvMaintenance, false)
+	if err != nil {
+		return nil, err
+	}
+
+	stateStore := os.Getenv(EnvStateStore)
+	if stateStore == "" {
+		stateStore = DefaultStateStore
+	}
+
+	switch stateStore {
+	case StateStoreFile, StateStoreConfigMap, StateStoreNone:
+	default:
+		return nil, fmt.Errorf("unknown state store %s, expected one of %s, %s and %s",
+			stateStore, StateStoreFile, StateStoreConfigMap, StateStoreNone)
+	}
+
+	stateFile := os.Getenv(EnvStateFile)
+	if stateFile == "" {
+		stateFile = DefaultStateFile
+	}
+
+	stateConfigMap := os.Getenv(EnvStateConfigMap)
+	if stateConfigMap == "" {
+		stateConfigMap = DefaultStateConfigMap
+	}
+
+	leaderElection, err := setupLeaderElection(discoveryNS)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Config{
-		DiscoverySTS:  discoverySTS,
-		DiscoveryNS:   discoveryNS,
+		SequencersList: sequencersList,
+		DiscoverySTS:   discoverySTS,
+		DiscoveryNS:    discoveryNS,
+
+		DiscoveryScheme:   discoveryScheme,
+		DiscoveryPortName: discoveryPortName,
+		DiscoveryPort:     discoveryPort,
+		ClusterDomain:     clusterDomain,
+
+		ExecutionURL:      os.Getenv(EnvExecutionURL),
+		ExecutionPortName: executionPortName,
+
 		CheckInterval: checkInterval,
 		MaxBlockTime:  maxBlockTime,
+
+		SequencerPriorities: sequencerPriorities,
+		Failback:            failback,
+
+		HandoffSyncTimeout: handoffSyncTimeout,
+		SwitchoverCooldown: switchoverCooldown,
+		MaxSwitchovers:     maxSwitchovers,
+		Quarantine:         quarantine,
+		BootstrapTimeout:   bootstrapTimeout,
+		DegradedBackoff:    degradedBackoff,
+		DegradedMaxBackoff: degradedMaxBackoff,
+		NotifyWebhookURL:   os.Getenv(EnvNotifyWebhookURL),
+
+		SafeHeadStallTimeout:  safeHeadStallTimeout,
+		SafeHeadHoldPromotion: safeHeadHoldPromotion,
+
+		RPC:            *rpc,
+		Health:         *health,
+		Maintenance:    maintenance,
+		StateStore:     stateStore,
+		StateFile:      stateFile,
+		StateConfigMap: stateConfigMap,
+		APIToken:       os.Getenv(EnvAPIToken),
+		LeaderElection: *leaderElection,
 	}, nil
 }
+
+func setupRPC(defaultNS string) (*RPC, error) {
+	timeout, err := durationFromEnv(EnvRPCTimeout, DefaultRPCTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	retries, err := intFromEnv(EnvRPCRetries, DefaultRPCRetries)
+	if err != nil {
+		return nil, err
+	}
+
+	backoff, err := durationFromEnv(EnvRPCBackoff, DefaultRPCBackoff)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBackoff, err := durationFromEnv(EnvRPCMaxBackoff, DefaultRPCMaxBackoff)
+	if err != nil {
+		return nil, err
+	}
+
+	if retries < 0 {
+		return nil, fmt.Errorf("rpc retries (%d) must not be negative", retries)
+	}
+
+	if maxBackoff < backoff {
+		return nil, fmt.Errorf("rpc max backoff (%s) must not be less than backoff (%s)", maxBackoff, backoff)
+	}
+
+	tls := TLS{
+		CAFile:     os.Getenv(EnvRPCTLSCAFile),
+		CertFile:   os.Getenv(EnvRPCTLSCertFile),
+		KeyFile:    os.Getenv(EnvRPCTLSKeyFile),
+		ServerName: os.Getenv(EnvRPCTLSServerName),
+	}
+
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		return nil, fmt.Errorf("both or neither of rpc tls cert file and key file must be provided")
+	}
+
+	jwtSecretFile := os.Getenv(EnvJWTSecretFile)
+	jwtSecret := os.Getenv(EnvJWTSecret)
+
+	if jwtSecretFile != "" && jwtSecret != "" {
+		return nil, fmt.Errorf("only one of jwt secret file and jwt secret can be provided")
+	}
+
+	jwtSecretKey := os.Getenv(EnvJWTSecretKey)
+	if jwtSecretKey == "" {
+		jwtSecretKey = DefaultJWTSecretKey
+	}
+
+	return &RPC{
+		Timeout:            timeout,
+		Retries:            retries,
+		Backoff:            backoff,
+		MaxBackoff:         maxBackoff,
+		JWTSecretFile:      jwtSecretFile,
+		JWTSecret:          jwtSecret,
+		JWTSecretKey:       jwtSecretKey,
+		JWTSecretNamespace: defaultNS,
+
+		ExecutionJWTSecretFile: os.Getenv(EnvExecutionJWTSecretFile),
+
+		TLS: tls,
+	}, nil
+}
+
+func setupHealth() (*Health, error) {
+	checksValue := os.Getenv(EnvHealthChecks)
+	if checksValue == "" {
+		checksValue = DefaultHealthChecks
+	}
+
+	var checks []string
+
+	for _, check := range strings.Split(checksValue, ",") {
+		switch check = strings.TrimSpace(check); check {
+		case "", "none":
+		case HealthCheckL1Head, HealthCheckSafeLag, HealthCheckFinalizedLag, HealthCheckPeers, HealthCheckRPCLatency,
+			HealthCheckExecution, HealthCheckExecutionPeers:
+			checks = append(checks, check)
+		default:
+			return nil, fmt.Errorf("unknown health check %s", check)
+		}
+	}
+
+	policy := os.Getenv(EnvHealthPolicy)
+	if policy == "" {
+		policy = DefaultHealthPolicy
+	}
+
+	if policy != HealthPolicyMin && policy != HealthPolicyMean {
+		return nil, fmt.Errorf("unknown health policy %s, expected %s or %s", policy, HealthPolicyMin, HealthPolicyMean)
+	}
+
+	degradedScore, err := floatFromEnv(EnvHealthDegradedScore, DefaultHealthDegradedScore)
+	if err != nil {
+		return nil, err
+	}
+
+	unhealthyScore, err := floatFromEnv(EnvHealthUnhealthyScore, DefaultHealthUnhealthyScore)
+	if err != nil {
+		return nil, err
+	}
+
+	if unhealthyScore < 0 || unhealthyScore > degradedScore || degradedScore > 1 {
+		return nil, fmt.Errorf("health scores must be 0 <= unhealthy (%g) <= degraded (%g) <= 1", unhealthyScore, degradedScore)
+	}
+
+	l1HeadAge, err := durationThresholdFromEnv(EnvHealthL1HeadWarn, DefaultHealthL1HeadWarn, EnvHealthL1HeadCrit, DefaultHealthL1HeadCrit)
+	if err != nil {
+		return nil, err
+	}
+
+	safeLag, err := intThresholdFromEnv(EnvHealthSafeLagWarn, DefaultHealthSafeLagWarn, EnvHealthSafeLagCrit, DefaultHealthSafeLagCrit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	finalizedLag, err := intThresholdFromEnv(EnvHealthFinalizedLagWarn, DefaultHealthFinalizedLagWarn, EnvHealthFinalizedLagCrit, DefaultHealthFinalizedLagCrit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := intThresholdFromEnv(EnvHealthPeersWarn, DefaultHealthPeersWarn, EnvHealthPeersCrit, DefaultHealthPeersCrit, true)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcLatency, err := durationThresholdFromEnv(EnvHealthRPCLatencyWarn, DefaultHealthRPCLatencyWarn, EnvHealthRPCLatencyCrit, DefaultHealthRPCLatencyCrit)
+	if err != nil {
+		return nil, err
+	}
+
+	executionLag, err := intThresholdFromEnv(EnvHealthExecutionLagWarn, DefaultHealthExecutionLagWarn, EnvHealthExecutionLagCrit, DefaultHealthExecutionLagCrit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	executionPeers, err := intThresholdFromEnv(EnvHealthExecutionPeersWarn, DefaultHealthExecutionPeersWarn, EnvHealthExecutionPeersCrit, DefaultHealthExecutionPeersCrit, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Health{
+		Checks:         checks,
+		Policy:         policy,
+		DegradedScore:  degradedScore,
+		UnhealthyScore: unhealthyScore,
+		L1HeadAge:      *l1HeadAge,
+		SafeLag:        *safeLag,
+		FinalizedLag:   *finalizedLag,
+		Peers:          *peers,
+		RPCLatency:     *rpcLatency,
+		ExecutionLag:   *executionLag,
+		ExecutionPeers: *executionPeers,
+	}, nil
+}
+
+func setupLeaderElection(defaultNS string) (*LeaderElection, error) {
+	enabled, err := boolFromEnv(EnvLeaderElection, false)
+	if err != nil {
+		return nil, err
+	}
+
+	name := os.Getenv(EnvLeaderElectionName)
+	if name == "" {
+		name = DefaultLeaderElectionName
+	}
+
+	namespace := os.Getenv(EnvLeaderElectionNS)
+	if namespace == "" {
+		namespace = defaultNS
+	}
+
+	// Pod name is injected with the downward API, hostname is the pod name otherwise
+	identity := os.Getenv(EnvPodName)
+	if identity == "" {
+		identity, err = os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hostname as leader election identity: %w", err)
+		}
+	}
+
+	leaseDuration, err := durationFromEnv(EnvLeaderElectionLeaseDuration, DefaultLeaderElectionLeaseDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	renewDeadline, err := durationFromEnv(EnvLeaderElectionRenewDeadline, DefaultLeaderElectionRenewDeadline)
+	if err != nil {
+		return nil, err
+	}
+
+	retryPeriod, err := durationFromEnv(EnvLeaderElectionRetryPeriod, DefaultLeaderElectionRetryPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	if leaseDuration <= renewDeadline {
+		return nil, fmt.Errorf("leader election lease duration (%s) must be greater than renew deadline (%s)",
+			leaseDuration, renewDeadline)
+	}
+
+	return &LeaderElection{
+		Enabled:       enabled,
+		Name:          name,
+		Namespace:     namespace,
+		Identity:      identity,
+		LeaseDuration: leaseDuration,
+		RenewDeadline: renewDeadline,
+		RetryPeriod:   retryPeriod,
+	}, nil
+}
+
+// durationFromEnv parses a duration from an environment variable, using defaultValue if it is unset.
+func durationFromEnv(key, defaultValue string) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		value = defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s (%s): %w", key, value, err)
+	}
+
+	return duration, nil
+}
+
+// boolFromEnv parses a boolean from an environment variable, using defaultValue if it is unset.
+func boolFromEnv(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s (%s): %w", key, value, err)
+	}
+
+	return b, nil
+}
+
+// intFromEnv parses an integer from an environment variable, using defaultValue if it is unset.
+func intFromEnv(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s (%s): %w", key, value, err)
+	}
+
+	return i, nil
+}
+
+// prioritiesFromEnv parses comma-separated name=priority pairs from an environment variable, nil if it is unset.
+func prioritiesFromEnv(key string) (map[string]int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	priorities := make(map[string]int)
+
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		name, priority, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("failed to parse %s (%s): expected name=priority", key, pair)
+		}
+
+		i, err := strconv.Atoi(strings.TrimSpace(priority))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s (%s): %w", key, pair, err)
+		}
+
+		priorities[strings.TrimSpace(name)] = i
+	}
+
+	return priorities, nil
+}
+
+// floatFromEnv parses a float from an environment variable, using defaultValue if it is unset.
+func floatFromEnv(key string, defaultValue float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s (%s): %w", key, value, err)
+	}
+
+	return f, nil
+}
+
+// durationThresholdFromEnv parses a threshold in seconds from duration environment variables, the warn duration must be shorter.
+func durationThresholdFromEnv(warnKey, warnDefault, critKey, critDefault string) (*Threshold, error) {
+	warn, err := durationFromEnv(warnKey, warnDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	crit, err := durationFromEnv(critKey, critDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	if warn >= crit {
+		return nil, fmt.Errorf("%s (%s) must be shorter than %s (%s)", warnKey, warn, critKey, crit)
+	}
+
+	return &Threshold{Warn: warn.Seconds(), Crit: crit.Seconds()}, nil
+}
+
+// intThresholdFromEnv parses a threshold from integer environment variables.
+// The warn value must be below the crit value, or above it if lower is worse.
+func intThresholdFromEnv(warnKey string, warnDefault int, critKey string, critDefault int, lowerIsWorse bool) (*Threshold, error) {
+	warn, err := intFromEnv(warnKey, warnDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	crit, err := intFromEnv(critKey, critDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	if lowerIsWorse && warn <= crit {
+		return nil, fmt.Errorf("%s (%d) must be greater than %s (%d)", warnKey, warn, critKey, crit)
+	}
+
+	if !lowerIsWorse && warn >= crit {
+		return nil, fmt.Errorf("%s (%d) must be less than %s (%d)", warnKey, warn, critKey, crit)
+	}
+
+	return &Threshold{Warn: float64(warn), Crit: float64(crit)}, nil
+}
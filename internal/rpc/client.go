@@ -0,0 +1,217 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+	"github.com/rss3-network/vsl-reconcile/pkg/metrics"
+)
+
+// API is the JSON-RPC API of the sequencers and their execution clients.
+type API interface {
+	CheckSequencerActive(ctx context.Context, sequencer string) (bool, error)
+	ActivateSequencer(ctx context.Context, sequencer string, unsafeHash string) error
+	DeactivateSequencer(ctx context.Context, sequencer string) (string, error)
+	GetSyncStatus(ctx context.Context, sequencer string) (*SyncStatus, error)
+	GetSyncStatusOnce(ctx context.Context, sequencer string) (*SyncStatus, error)
+	GetOPSyncStatus(ctx context.Context, sequencer string) (string, int64, bool, error)
+	GetPeerCount(ctx context.Context, sequencer string) (int, error)
+	GetBlockNumber(ctx context.Context, endpoint string) (int64, error)
+	GetSyncing(ctx context.Context, endpoint string) (bool, error)
+	GetNetPeerCount(ctx context.Context, endpoint string) (int, error)
+}
+
+var _ API = (*Client)(nil)
+
+// Client sends JSON-RPC requests to sequencers.
+// Failed requests are retried with exponential backoff and jitter, until the retry budget or the context runs out.
+// A nil or zero Client sends every request once, bounded by the context only.
+type Client struct {
+	httpClient *http.Client
+
+	timeout    time.Duration
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	// jwtSecret signs every request with a fresh token, requests are not authenticated if nil.
+	// Requests to op-geth are signed with executionJWTSecret instead, so they never carry the op-node token
+	jwtSecret          []byte
+	executionJWTSecret []byte
+}
+
+func NewClient(cfg config.RPC) (*Client, error) {
+	transport, err := newTransport(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	jwtSecret, err := loadJWTSecret(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	executionJWTSecret, err := loadJWTSecretFile(cfg.ExecutionJWTSecretFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		httpClient: &http.Client{Transport: transport},
+		timeout:    cfg.Timeout,
+		retries:    cfg.Retries,
+		backoff:    cfg.Backoff,
+		maxBackoff: cfg.MaxBackoff,
+		jwtSecret:  jwtSecret,
+
+		executionJWTSecret: executionJWTSecret,
+	}, nil
+}
+
+// once returns a copy of the client which never retries, so a request can be timed
+func (c *Client) once() *Client {
+	if c == nil {
+		return nil
+	}
+
+	once := *c
+	once.retries = 0
+
+	return &once
+}
+
+// execution returns a copy of the client for op-geth endpoints, which signs requests with the execution secret
+func (c *Client) execution() *Client {
+	if c == nil {
+		return nil
+	}
+
+	execution := *c
+	execution.jwtSecret = c.executionJWTSecret
+
+	return &execution
+}
+
+// call: The function wraps method and params to JSON RPC call format, and then send to rpcEndpoint.
+// JSON-RPC errors are returned by the sequencer itself, so they are never retried.
+func call[T any](ctx context.Context, c *Client, method string, params []any, rpcEndpoint string) (*T, error) {
+	if c == nil {
+		c = &Client{}
+	}
+
+	var rpcErr *Error
+
+	for attempt := 0; ; attempt++ {
+		startTime := time.Now()
+		result, err := request[T](ctx, c, method, params, rpcEndpoint)
+
+		metrics.RPCDuration.WithLabelValues(method).Observe(time.Since(startTime).Seconds())
+
+		if err == nil {
+			// Success
+			return result, nil
+		}
+
+		metrics.RPCErrors.WithLabelValues(method).Inc()
+
+		if attempt >= c.retries || errors.As(err, &rpcErr) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w, last error: %w", ctx.Err(), err)
+		case <-time.After(c.backoffDelay(attempt)):
+		}
+	}
+}
+
+// request: Send a single JSON RPC request to rpcEndpoint.
+func request[T any](ctx context.Context, c *Client, method string, params []any, rpcEndpoint string) (*T, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	reqData := JSONRPCRequestData{
+		Version: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      1, // Only important for WS-RPC calls.
+	}
+
+	reqDataBytes, err := json.Marshal(&reqData)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request data: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpcEndpoint, bytes.NewBuffer(reqDataBytes))
+	if err != nil {
+		return nil, fmt.Errorf("initialize request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.jwtSecret != nil {
+		token, err := jwtToken(c.jwtSecret, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+
+	var resObj JSONRPCResponse[T]
+
+	err = json.NewDecoder(res.Body).Decode(&resObj)
+	_ = res.Body.Close() // Close to prevent memory leak
+
+	if err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if resObj.Error != nil {
+		return nil, resObj.Error
+	}
+
+	return resObj.Result, nil
+}
+
+// backoffDelay is the wait before retrying a failed attempt, doubling from backoff up to maxBackoff.
+// Half of it is random, so sequencers are not retried in lockstep.
+func (c *Client) backoffDelay(attempt int) time.Duration {
+	delay := c.backoff
+
+	for i := 0; i < attempt && delay < c.maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if c.maxBackoff > 0 && delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + rand.N(delay/2+1)
+}
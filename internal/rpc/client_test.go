@@ -0,0 +1,163 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+)
+
+var testClient = &Client{
+	timeout:    5 * time.Second,
+	retries:    1,
+	backoff:    10 * time.Millisecond,
+	maxBackoff: 100 * time.Millisecond,
+}
+
+func TestClientRetry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		attempts atomic.Int32
+		failures atomic.Int32
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+
+		if failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":true}`))
+	}))
+
+	defer server.Close()
+
+	client, err := NewClient(config.RPC{
+		Timeout:    time.Second,
+		Retries:    2,
+		Backoff:    10 * time.Millisecond,
+		MaxBackoff: 20 * time.Millisecond,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Situation 1: Fails twice, should succeed with the last retry
+	failures.Store(2)
+
+	isActive, err := client.CheckSequencerActive(context.Background(), server.URL)
+
+	if err != nil || !isActive || attempts.Load() != 3 {
+		t.Log("should succeed after retries", isActive, err, attempts.Load())
+
+		t.Fail()
+	}
+
+	// Situation 2: Fails three times, should run out of retries
+	attempts.Store(0)
+
+	failures.Store(3)
+
+	_, err = client.CheckSequencerActive(context.Background(), server.URL)
+
+	if err == nil || attempts.Load() != 3 {
+		t.Log("should fail after retries", err, attempts.Load())
+
+		t.Fail()
+	}
+
+	// Situation 3: Fails once with a single attempt, should not retry
+	attempts.Store(0)
+
+	failures.Store(1)
+
+	if _, err = client.GetSyncStatusOnce(context.Background(), server.URL); err == nil || attempts.Load() != 1 {
+		t.Log("should fail without retries", err, attempts.Load())
+
+		t.Fail()
+	}
+
+	// Situation 4: Context is done, should stop retrying
+	attempts.Store(0)
+
+	failures.Store(100)
+
+	client.retries = 100
+	client.backoff = time.Second
+	client.maxBackoff = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	startTime := time.Now()
+
+	_, err = client.CheckSequencerActive(ctx, server.URL)
+
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(startTime) > time.Second {
+		t.Log("should stop with the context", err, time.Since(startTime))
+
+		t.Fail()
+	}
+}
+
+func TestClientError(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"not available"}}`))
+	}))
+
+	defer server.Close()
+
+	// Situation 1: JSON-RPC error, should not retry
+	_, err := testClient.CheckSequencerActive(context.Background(), server.URL)
+
+	var rpcErr *Error
+
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32601 || attempts.Load() != 1 {
+		t.Log("should return the error without retrying", err, attempts.Load())
+
+		t.Fail()
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		backoff:    time.Second,
+		maxBackoff: 5 * time.Second,
+	}
+
+	// Situation 1: Should double with jitter, up to the max backoff
+	for attempt, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		delay := client.backoffDelay(attempt)
+
+		if delay < expected/2 || delay > expected {
+			t.Log("backoff delay out of range", attempt, delay)
+
+			t.Fail()
+		}
+	}
+
+	// Situation 2: Zero client, should not wait
+	if delay := (&Client{}).backoffDelay(3); delay != 0 {
+		t.Log("zero client should not wait", delay)
+
+		t.Fail()
+	}
+}
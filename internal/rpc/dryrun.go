@@ -0,0 +1,92 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/dryrun"
+)
+
+var _ API = (*DryRun)(nil)
+
+// DryRun wraps a client in dry-run mode, so sequencers are never started or stopped.
+// Starting and stopping sequencers is recorded in the plan instead, and their active state is simulated,
+// so later decisions are made as if the planned actions had been performed. Everything else is passed through.
+type DryRun struct {
+	API
+
+	mu     sync.Mutex
+	active map[string]bool // Simulated active state of the sequencers with planned actions
+}
+
+func NewDryRun(client API) *DryRun {
+	return &DryRun{
+		API:    client,
+		active: make(map[string]bool),
+	}
+}
+
+// CheckSequencerActive reports the simulated state of sequencers with planned actions.
+func (d *DryRun) CheckSequencerActive(ctx context.Context, sequencer string) (bool, error) {
+	if isActive, ok := d.simulated(sequencer); ok {
+		return isActive, nil
+	}
+
+	return d.API.CheckSequencerActive(ctx, sequencer)
+}
+
+// ActivateSequencer plans starting a sequencer.
+func (d *DryRun) ActivateSequencer(_ context.Context, sequencer string, unsafeHash string) error {
+	dryrun.Record(dryrun.Action{
+		Kind:   dryrun.KindRPC,
+		Target: sequencer,
+		Method: "admin_startSequencer",
+		Params: []string{unsafeHash},
+	})
+
+	d.simulate(sequencer, true)
+
+	return nil
+}
+
+// DeactivateSequencer plans stopping a sequencer, and gets the unsafe hash from op sync status instead.
+func (d *DryRun) DeactivateSequencer(ctx context.Context, sequencer string) (string, error) {
+	isActive, err := d.CheckSequencerActive(ctx, sequencer)
+	if err != nil {
+		return "", err
+	} else if !isActive {
+		return "", fmt.Errorf("sequencer not running")
+	}
+
+	unsafeHash, _, _, err := d.GetOPSyncStatus(ctx, sequencer)
+	if err != nil {
+		return "", err
+	}
+
+	dryrun.Record(dryrun.Action{
+		Kind:   dryrun.KindRPC,
+		Target: sequencer,
+		Method: "admin_stopSequencer",
+	})
+
+	d.simulate(sequencer, false)
+
+	return unsafeHash, nil
+}
+
+func (d *DryRun) simulate(sequencer string, isActive bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.active[sequencer] = isActive
+}
+
+func (d *DryRun) simulated(sequencer string) (bool, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	isActive, ok := d.active[sequencer]
+
+	return isActive, ok
+}
@@ -0,0 +1,102 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/dryrun"
+	"github.com/rss3-network/vsl-reconcile/test"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	// Prepare mock sequencer
+	ms, endpoint, err := test.NewMockSequencer()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer ms.Close()
+
+	client := NewDryRun(testClient)
+
+	ms.SetIsWithAdmin(true)
+
+	ms.SetIsActivated(true)
+
+	ms.SetIsReady(true)
+
+	ms.SetUnsafeHash("unsafe-hash-1")
+
+	// Situation 1: Sequencers without planned actions report their actual state
+	if isActive, err := client.CheckSequencerActive(context.Background(), endpoint); err != nil || !isActive {
+		t.Log("should be active", err)
+
+		t.Fail()
+	}
+
+	// Situation 2: Stopping is planned with the unsafe hash from op sync status, the sequencer keeps running
+	unsafeHash, err := client.DeactivateSequencer(context.Background(), endpoint)
+
+	if err != nil || unsafeHash != "unsafe-hash-1" {
+		t.Log("unsafe hash mismatch", unsafeHash, err)
+
+		t.Fail()
+	}
+
+	if !ms.GetIsActivated() {
+		t.Log("should not be stopped")
+
+		t.Fail()
+	}
+
+	if isActive, err := client.CheckSequencerActive(context.Background(), endpoint); err != nil || isActive {
+		t.Log("should be simulated inactive", err)
+
+		t.Fail()
+	}
+
+	// Situation 3: Stopping a simulated inactive sequencer fails like the sequencer would
+	if _, err = client.DeactivateSequencer(context.Background(), endpoint); err == nil {
+		t.Log("should be error")
+
+		t.Fail()
+	}
+
+	// Situation 4: Starting is planned, the sequencer is never started
+	ms.SetIsActivated(false)
+
+	if err = client.ActivateSequencer(context.Background(), endpoint, "unsafe-hash-1"); err != nil {
+		t.Log("should no error", err)
+
+		t.Fail()
+	}
+
+	if ms.GetIsActivated() {
+		t.Log("should not be started")
+
+		t.Fail()
+	}
+
+	if isActive, err := client.CheckSequencerActive(context.Background(), endpoint); err != nil || !isActive {
+		t.Log("should be simulated active", err)
+
+		t.Fail()
+	}
+
+	var planned []string
+
+	for _, action := range dryrun.Plan() {
+		if action.Target == endpoint {
+			planned = append(planned, action.Method)
+		}
+	}
+
+	if len(planned) != 2 || planned[0] != "admin_stopSequencer" || planned[1] != "admin_startSequencer" {
+		t.Log("plan mismatch", planned)
+
+		t.Fail()
+	}
+}
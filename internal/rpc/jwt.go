@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+	"github.com/rss3-network/vsl-reconcile/pkg/kube"
+)
+
+// jwtSecretLength is the length of the secret shared with op-node, which is 32 bytes hex encoded
+const jwtSecretLength = 32
+
+// jwtHeader is the base64url encoded JWT header of HS256 tokens
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// loadJWTSecret loads the JWT secret from a file or a Kubernetes Secret, nil if neither is configured
+func loadJWTSecret(ctx context.Context, cfg config.RPC) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch {
+	case cfg.JWTSecretFile != "":
+		data, err = os.ReadFile(cfg.JWTSecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("read jwt secret file: %w", err)
+		}
+	case cfg.JWTSecret != "":
+		clientset, err := kube.Client()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize kubernetes client: %w", err)
+		}
+
+		data, err = kube.SecretData(ctx, clientset, cfg.JWTSecretNamespace, cfg.JWTSecret, cfg.JWTSecretKey)
+		if err != nil {
+			return nil, fmt.Errorf("get jwt secret: %w", err)
+		}
+	default:
+		return nil, nil
+	}
+
+	return parseJWTSecret(data)
+}
+
+// loadJWTSecretFile loads the JWT secret from path, nil if it is empty
+func loadJWTSecretFile(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwt secret file: %w", err)
+	}
+
+	return parseJWTSecret(data)
+}
+
+// parseJWTSecret decodes a hex encoded secret, optionally 0x prefixed, as op-node does
+func parseJWTSecret(data []byte) ([]byte, error) {
+	secret, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("decode jwt secret: %w", err)
+	}
+
+	if len(secret) != jwtSecretLength {
+		return nil, fmt.Errorf("invalid jwt secret length %d, expected %d bytes", len(secret), jwtSecretLength)
+	}
+
+	return secret, nil
+}
+
+// jwtToken signs a HS256 token issued at now, op-node only accepts tokens issued within a minute
+func jwtToken(secret []byte, now time.Time) (string, error) {
+	claims, err := json.Marshal(struct {
+		IssuedAt int64 `json:"iat"`
+	}{
+		IssuedAt: now.Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal jwt claims: %w", err)
+	}
+
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,137 @@
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+)
+
+const testJWTSecret = "0x7365637265742d7365637265742d7365637265742d7365637265742d73656372\n"
+
+func TestParseJWTSecret(t *testing.T) {
+	t.Parallel()
+
+	// Situation 1: 0x prefixed with a trailing newline, as written by op-node
+	secret, err := parseJWTSecret([]byte(testJWTSecret))
+
+	if err != nil || len(secret) != jwtSecretLength {
+		t.Log("should parse secret", err)
+
+		t.Fail()
+	}
+
+	// Situation 2: Too short
+	if _, err = parseJWTSecret([]byte("0x1234")); err == nil {
+		t.Log("should be error")
+
+		t.Fail()
+	}
+
+	// Situation 3: Not hex encoded
+	if _, err = parseJWTSecret([]byte(strings.Repeat("zz", jwtSecretLength))); err == nil {
+		t.Log("should be error")
+
+		t.Fail()
+	}
+}
+
+func TestJWTAuthentication(t *testing.T) {
+	t.Parallel()
+
+	secret, err := parseJWTSecret([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secretFile := filepath.Join(t.TempDir(), "jwt.hex")
+
+	if err = os.WriteFile(secretFile, []byte(testJWTSecret), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var authorization atomic.Value
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		authorization.Store(req.Header.Get("Authorization"))
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":true}`))
+	}))
+
+	defer server.Close()
+
+	client, err := NewClient(config.RPC{
+		JWTSecretFile: secretFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Situation 1: Should send a token issued now, signed by the secret
+	if _, err = client.CheckSequencerActive(context.Background(), server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(authorization.Load().(string), "Bearer "), ".")
+
+	if len(parts) != 3 {
+		t.Fatal("invalid token", authorization.Load())
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+
+	if parts[2] != base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) {
+		t.Log("signature mismatch", authorization.Load())
+
+		t.Fail()
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims struct {
+		IssuedAt int64 `json:"iat"`
+	}
+
+	if err = json.Unmarshal(payload, &claims); err != nil || time.Since(time.Unix(claims.IssuedAt, 0)).Abs() > 5*time.Second {
+		t.Log("invalid claims", string(payload), err)
+
+		t.Fail()
+	}
+
+	// Situation 2: Requests to op-geth should never carry the op-node token
+	if _, err = client.GetSyncing(context.Background(), server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	if authorization.Load() != "" {
+		t.Log("should not send the op-node token to op-geth", authorization.Load())
+
+		t.Fail()
+	}
+
+	// Situation 3: No secret, should not authenticate
+	if _, err = testClient.CheckSequencerActive(context.Background(), server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	if authorization.Load() != "" {
+		t.Log("should not authenticate", authorization.Load())
+
+		t.Fail()
+	}
+}
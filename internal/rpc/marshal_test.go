@@ -91,24 +91,32 @@ func TestMarshalOPSyncStatus(t *testing.T) {
 }
 `
 
-	var opSyncStatus JSONRPCResponse[struct { // Ignore irrelevant fields
-		HeadL1 struct {
-			Hash       string `json:"hash"`
-			Number     int    `json:"number"`
-			ParentHash string `json:"parentHash"`
-			Timestamp  int64  `json:"timestamp"` // For check if sequencer is ready to be activated ( 12s * 3 )
-		} `json:"head_l1"`
-		UnsafeL2 struct {
-			Hash   string `json:"hash"`
-			Number int64  `json:"number"`
-			// Timestamp int64 `json:"timestamp"` // Not for isReady status reference
-		} `json:"unsafe_l2"`
-	}]
+	var opSyncStatus JSONRPCResponse[SyncStatus]
 
 	err := json.Unmarshal([]byte(rpcResponse), &opSyncStatus)
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	if opSyncStatus.Result.HeadL1.Timestamp != 1714122348 {
+		t.Log("head l1 timestamp mismatch", opSyncStatus.Result.HeadL1.Timestamp)
+		t.Fail()
+	}
+
+	if opSyncStatus.Result.UnsafeL2.Number != 2730980 {
+		t.Log("unsafe l2 number mismatch", opSyncStatus.Result.UnsafeL2.Number)
+		t.Fail()
+	}
+
+	if opSyncStatus.Result.SafeL2.Number != 2730824 {
+		t.Log("safe l2 number mismatch", opSyncStatus.Result.SafeL2.Number)
+		t.Fail()
+	}
+
+	if opSyncStatus.Result.FinalizedL2.Number != 2730317 {
+		t.Log("finalized l2 number mismatch", opSyncStatus.Result.FinalizedL2.Number)
+		t.Fail()
+	}
+
 	t.Log(opSyncStatus.Result)
 }
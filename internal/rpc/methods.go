@@ -1,84 +1,17 @@
 package rpc
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
-	"time"
 )
 
-// jsonRPCCall: The function wraps method and params to JSON RPC call format, and then send to rpcEndpoint .
-func jsonRPCCall[T any](method string, params []string, rpcEndpoint string) (*T, error) {
-	var failCount = 0
-
-	var returnErr error
-
-	for failCount < JSONRPCCallFailRetry {
-		if failCount > 0 {
-			time.Sleep(JSONRPCCallRequestTimeout)
-		}
-
-		failCount++
-
-		reqData := JSONRPCRequestData{
-			Version: "2.0",
-			Method:  method,
-			Params:  params,
-			ID:      1, // Only important for WS-RPC calls.
-		}
-
-		reqDataBytes, err := json.Marshal(&reqData)
-		if err != nil {
-			returnErr = fmt.Errorf("marshal request data: %w", err)
-			continue
-		}
-
-		req, err := http.NewRequestWithContext(context.Background(), "POST", rpcEndpoint, bytes.NewBuffer(reqDataBytes))
-		if err != nil {
-			returnErr = fmt.Errorf("initialize request: %w", err)
-			continue
-		}
-
-		req.Header.Set("Content-Type", "application/json")
-
-		res, err := (&http.Client{
-			Timeout: JSONRPCCallRequestTimeout,
-		}).Do(req)
-		if err != nil {
-			returnErr = fmt.Errorf("execute request: %w", err)
-			continue
-		}
-
-		var resObj JSONRPCResponse[T]
-
-		err = json.NewDecoder(res.Body).Decode(&resObj)
-		_ = res.Body.Close() // Close to prevent memory leak
-
-		if err != nil {
-			returnErr = fmt.Errorf("decode response: %w", err)
-			continue
-		}
-
-		if resObj.Error != nil {
-			returnErr = fmt.Errorf("request error %d: %s", resObj.Error.Code, resObj.Error.Message)
-			continue
-		}
-
-		// Success
-		return resObj.Result, nil
-	}
-
-	return nil, returnErr
-}
-
 // CheckSequencerActive : Check if a sequencer is in active state
 // {"jsonrpc":"2.0","id":1,"result":true} or {"jsonrpc":"2.0","id":1,"result":false}
 // Sequencer can have some other status like just syncing as backup node, in which case it might print error like
 // {"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"the method admin_sequencerActive does not exist/is not available"}}
-func CheckSequencerActive(sequencer string) (bool, error) {
-	isActive, err := jsonRPCCall[bool]("admin_sequencerActive", []string{}, sequencer)
+func (c *Client) CheckSequencerActive(ctx context.Context, sequencer string) (bool, error) {
+	isActive, err := call[bool](ctx, c, "admin_sequencerActive", []any{}, sequencer)
 	if err != nil {
 		return false, fmt.Errorf("jsonrpc request failed: %w", err)
 	} else if isActive == nil {
@@ -90,8 +23,8 @@ func CheckSequencerActive(sequencer string) (bool, error) {
 
 // ActivateSequencer : Activate a sequencer as primary sequencer.
 // Seems like we don't care about the result if only there's no errors.
-func ActivateSequencer(sequencer string, unsafeHash string) error {
-	_, err := jsonRPCCall[any]("admin_startSequencer", []string{unsafeHash}, sequencer)
+func (c *Client) ActivateSequencer(ctx context.Context, sequencer string, unsafeHash string) error {
+	_, err := call[any](ctx, c, "admin_startSequencer", []any{unsafeHash}, sequencer)
 	if err != nil {
 		return fmt.Errorf("jsonrpc request failed: %w", err)
 	}
@@ -100,8 +33,8 @@ func ActivateSequencer(sequencer string, unsafeHash string) error {
 }
 
 // DeactivateSequencer : Deactivate a sequencer and get current unsafe hash.
-func DeactivateSequencer(sequencer string) (string, error) {
-	unsafeHash, err := jsonRPCCall[string]("admin_stopSequencer", []string{}, sequencer)
+func (c *Client) DeactivateSequencer(ctx context.Context, sequencer string) (string, error) {
+	unsafeHash, err := call[string](ctx, c, "admin_stopSequencer", []any{}, sequencer)
 	if err != nil {
 		return "", fmt.Errorf("jsonrpc request failed: %w", err)
 	} else if unsafeHash == nil {
@@ -111,30 +44,83 @@ func DeactivateSequencer(sequencer string) (string, error) {
 	return *unsafeHash, nil
 }
 
+// GetSyncStatus : Get op sync status of a sequencer.
+func (c *Client) GetSyncStatus(ctx context.Context, sequencer string) (*SyncStatus, error) {
+	syncStatus, err := call[SyncStatus](ctx, c, "optimism_syncStatus", []any{}, sequencer)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc request failed: %w", err)
+	} else if syncStatus == nil {
+		return nil, fmt.Errorf("unknown response nil")
+	}
+
+	return syncStatus, nil
+}
+
+// GetSyncStatusOnce : Get op sync status of a sequencer with a single attempt, which is never retried.
+func (c *Client) GetSyncStatusOnce(ctx context.Context, sequencer string) (*SyncStatus, error) {
+	return c.once().GetSyncStatus(ctx, sequencer)
+}
+
 // GetOPSyncStatus : Get unsafe L2 Head from op sync status.
 // This shouldn't be common as we can get unsafe header from deactivation request,
 // but sometimes deactivation can fail. So use this as a fallback.
-func GetOPSyncStatus(sequencer string) (string, int64, bool, error) {
-	syncStatus, err := jsonRPCCall[struct { // Ignore irrelevant fields
-		HeadL1 struct {
-			// Hash       string `json:"hash"`
-			// Number     int    `json:"number"`
-			// ParentHash string `json:"parentHash"`
-			Timestamp int64 `json:"timestamp"` // For check if sequencer is ready to be activated ( 12s * 3 )
-		} `json:"head_l1"`
-		UnsafeL2 struct {
-			Hash   string `json:"hash"`
-			Number int64  `json:"number"`
-			// Timestamp int64 `json:"timestamp"` // Not for isReady status reference
-		} `json:"unsafe_l2"`
-	}]("optimism_syncStatus", []string{}, sequencer)
+func (c *Client) GetOPSyncStatus(ctx context.Context, sequencer string) (string, int64, bool, error) {
+	syncStatus, err := c.GetSyncStatus(ctx, sequencer)
 	if err != nil {
-		return "", 0, false, fmt.Errorf("jsonrpc request failed: %w", err)
-	} else if syncStatus == nil {
-		return "", 0, false, fmt.Errorf("unknown response nil")
+		return "", 0, false, err
 	}
 
 	return syncStatus.UnsafeL2.Hash, syncStatus.UnsafeL2.Number, // unsafe hash
-		time.Now().Unix()-syncStatus.HeadL1.Timestamp < MaxMainnetBlockTimestampLateTolerance, // is sequencer sync with mainnet (max tolerance 3 blocks behind) and ready to be activated
+		syncStatus.IsReady(), // is sequencer sync with mainnet (max tolerance 3 blocks behind) and ready to be activated
 		nil
 }
+
+// GetPeerCount : Get the number of peers the sequencer is connected to over p2p.
+func (c *Client) GetPeerCount(ctx context.Context, sequencer string) (int, error) {
+	peers, err := call[PeerDump](ctx, c, "opp2p_peers", []any{true}, sequencer)
+	if err != nil {
+		return 0, fmt.Errorf("jsonrpc request failed: %w", err)
+	} else if peers == nil {
+		return 0, fmt.Errorf("unknown response nil")
+	}
+
+	return peers.TotalConnected, nil
+}
+
+// GetBlockNumber : Get the latest block number of an execution client.
+func (c *Client) GetBlockNumber(ctx context.Context, endpoint string) (int64, error) {
+	blockNumber, err := call[string](ctx, c.execution(), "eth_blockNumber", []any{}, endpoint)
+	if err != nil {
+		return 0, fmt.Errorf("jsonrpc request failed: %w", err)
+	} else if blockNumber == nil {
+		return 0, fmt.Errorf("unknown response nil")
+	}
+
+	return parseQuantity(*blockNumber)
+}
+
+// GetSyncing : Check if an execution client is syncing, eth_syncing returns false or the sync progress.
+func (c *Client) GetSyncing(ctx context.Context, endpoint string) (bool, error) {
+	syncing, err := call[json.RawMessage](ctx, c.execution(), "eth_syncing", []any{}, endpoint)
+	if err != nil {
+		return false, fmt.Errorf("jsonrpc request failed: %w", err)
+	} else if syncing == nil {
+		return false, fmt.Errorf("unknown response nil")
+	}
+
+	return string(*syncing) != "false", nil
+}
+
+// GetNetPeerCount : Get the number of peers an execution client is connected to.
+func (c *Client) GetNetPeerCount(ctx context.Context, endpoint string) (int, error) {
+	peerCount, err := call[string](ctx, c.execution(), "net_peerCount", []any{}, endpoint)
+	if err != nil {
+		return 0, fmt.Errorf("jsonrpc request failed: %w", err)
+	} else if peerCount == nil {
+		return 0, fmt.Errorf("unknown response nil")
+	}
+
+	peers, err := parseQuantity(*peerCount)
+
+	return int(peers), err
+}
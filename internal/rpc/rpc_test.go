@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"testing"
 
 	"github.com/rss3-network/vsl-reconcile/test"
@@ -22,7 +23,7 @@ func TestCheckSequencerActive(t *testing.T) {
 
 	ms.SetIsWithAdmin(false)
 
-	isSequencerActive, err := CheckSequencerActive(endpoint)
+	isSequencerActive, err := testClient.CheckSequencerActive(context.Background(), endpoint)
 
 	if err == nil {
 		t.Log("should be error")
@@ -40,7 +41,7 @@ func TestCheckSequencerActive(t *testing.T) {
 
 	ms.SetIsActivated(false)
 
-	isSequencerActive, err = CheckSequencerActive(endpoint)
+	isSequencerActive, err = testClient.CheckSequencerActive(context.Background(), endpoint)
 
 	if err != nil {
 		t.Log("should no error", err)
@@ -58,7 +59,7 @@ func TestCheckSequencerActive(t *testing.T) {
 
 	ms.SetIsActivated(true)
 
-	isSequencerActive, err = CheckSequencerActive(endpoint)
+	isSequencerActive, err = testClient.CheckSequencerActive(context.Background(), endpoint)
 
 	if err != nil {
 		t.Log("should no error", err)
@@ -88,7 +89,7 @@ func TestActivateSequencer(t *testing.T) {
 
 	ms.SetIsWithAdmin(false)
 
-	err = ActivateSequencer(endpoint, "unsafe-hash")
+	err = testClient.ActivateSequencer(context.Background(), endpoint, "unsafe-hash")
 
 	if err == nil {
 		t.Log("should be error")
@@ -101,7 +102,7 @@ func TestActivateSequencer(t *testing.T) {
 
 	ms.SetIsActivated(false)
 
-	err = ActivateSequencer(endpoint, "unsafe-hash")
+	err = testClient.ActivateSequencer(context.Background(), endpoint, "unsafe-hash")
 
 	if err != nil {
 		t.Log("should no error", err)
@@ -114,7 +115,7 @@ func TestActivateSequencer(t *testing.T) {
 
 	ms.SetIsActivated(true)
 
-	err = ActivateSequencer(endpoint, "unsafe-hash")
+	err = testClient.ActivateSequencer(context.Background(), endpoint, "unsafe-hash")
 
 	if err == nil {
 		t.Log("should be error")
@@ -141,7 +142,7 @@ func TestDeactivateSequencer(t *testing.T) {
 
 	ms.SetUnsafeHash("unsafe-hash-1")
 
-	unsafeHash, err := DeactivateSequencer(endpoint)
+	unsafeHash, err := testClient.DeactivateSequencer(context.Background(), endpoint)
 
 	if err == nil {
 		t.Log("should be error")
@@ -161,7 +162,7 @@ func TestDeactivateSequencer(t *testing.T) {
 
 	ms.SetUnsafeHash("unsafe-hash-2")
 
-	unsafeHash, err = DeactivateSequencer(endpoint)
+	unsafeHash, err = testClient.DeactivateSequencer(context.Background(), endpoint)
 
 	if err == nil {
 		t.Log("should be error")
@@ -181,7 +182,7 @@ func TestDeactivateSequencer(t *testing.T) {
 
 	ms.SetUnsafeHash("unsafe-hash-3")
 
-	unsafeHash, err = DeactivateSequencer(endpoint)
+	unsafeHash, err = testClient.DeactivateSequencer(context.Background(), endpoint)
 
 	if err != nil {
 		t.Log("should no error", err)
@@ -213,7 +214,7 @@ func TestGetOPSyncStatus(t *testing.T) {
 
 	ms.SetUnsafeHash("unsafe-hash-1")
 
-	unsafeHash, _, isReady, err := GetOPSyncStatus(endpoint)
+	unsafeHash, _, isReady, err := testClient.GetOPSyncStatus(context.Background(), endpoint)
 
 	if err != nil {
 		t.Log("should no error", err)
@@ -236,7 +237,7 @@ func TestGetOPSyncStatus(t *testing.T) {
 
 	ms.SetUnsafeHash("unsafe-hash-2")
 
-	unsafeHash, _, isReady, err = GetOPSyncStatus(endpoint)
+	unsafeHash, _, isReady, err = testClient.GetOPSyncStatus(context.Background(), endpoint)
 
 	if err != nil {
 		t.Log("should no error", err)
@@ -253,3 +254,58 @@ func TestGetOPSyncStatus(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestExecutionClient(t *testing.T) {
+	t.Parallel()
+
+	// Prepare mock execution client
+
+	ms, endpoint, err := test.NewMockSequencer()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer ms.Close()
+
+	// Situation 1: execution client not available
+
+	if _, err = testClient.GetBlockNumber(context.Background(), endpoint); err == nil {
+		t.Log("should error")
+		t.Fail()
+	}
+
+	// Situation 2: execution client synced
+
+	ms.SetExecutionBlock(436)
+
+	ms.SetExecutionPeerCount(5)
+
+	blockNumber, err := testClient.GetBlockNumber(context.Background(), endpoint)
+
+	if err != nil || blockNumber != 436 {
+		t.Log("block number mismatch", blockNumber, err)
+		t.Fail()
+	}
+
+	peerCount, err := testClient.GetNetPeerCount(context.Background(), endpoint)
+
+	if err != nil || peerCount != 5 {
+		t.Log("peer count mismatch", peerCount, err)
+		t.Fail()
+	}
+
+	if syncing, err := testClient.GetSyncing(context.Background(), endpoint); err != nil || syncing {
+		t.Log("should not be syncing", err)
+		t.Fail()
+	}
+
+	// Situation 3: execution client syncing
+
+	ms.SetIsSyncing(true)
+
+	if syncing, err := testClient.GetSyncing(context.Background(), endpoint); err != nil || !syncing {
+		t.Log("should be syncing", err)
+		t.Fail()
+	}
+}
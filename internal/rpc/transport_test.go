@@ -0,0 +1,146 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+)
+
+func TestTLS(t *testing.T) {
+	t.Parallel()
+
+	// Prepare a self-signed certificate, used as CA, server and client certificate
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sequencer"},
+		DNSNames:              []string{"sequencer.vsl.svc"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+
+	if err = os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(certPEM)
+
+	// Prepare a sequencer requiring client certificates
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":true}`))
+	}))
+
+	server.TLS = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}
+
+	server.StartTLS()
+
+	defer server.Close()
+
+	// Situation 1: CA, client certificate and server name are provided, should succeed
+	client, err := NewClient(config.RPC{
+		TLS: config.TLS{
+			CAFile:     certFile,
+			CertFile:   certFile,
+			KeyFile:    keyFile,
+			ServerName: "sequencer.vsl.svc",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = client.CheckSequencerActive(context.Background(), server.URL); err != nil {
+		t.Log("should no error", err)
+
+		t.Fail()
+	}
+
+	// Situation 2: Server name is not overridden, should fail to verify the IP address
+	client, err = NewClient(config.RPC{
+		TLS: config.TLS{
+			CAFile:   certFile,
+			CertFile: certFile,
+			KeyFile:  keyFile,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = client.CheckSequencerActive(context.Background(), server.URL); err == nil {
+		t.Log("should be error")
+
+		t.Fail()
+	}
+
+	// Situation 3: No client certificate, should be rejected
+	client, err = NewClient(config.RPC{
+		TLS: config.TLS{
+			CAFile:     certFile,
+			ServerName: "sequencer.vsl.svc",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = client.CheckSequencerActive(context.Background(), server.URL); err == nil {
+		t.Log("should be error")
+
+		t.Fail()
+	}
+}
@@ -1,18 +1,78 @@
 package rpc
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type JSONRPCRequestData struct {
-	Version string   `json:"jsonrpc"` // 2.0
-	Method  string   `json:"method"`
-	Params  []string `json:"params"`
-	ID      uint     `json:"id"` // Request ID
+	Version string `json:"jsonrpc"` // 2.0
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+	ID      uint   `json:"id"` // Request ID
 }
 
 type JSONRPCResponse[T any] struct {
-	Version string    `json:"jsonrpc"` // 2.0
-	ID      uint      `json:"id"`      // Request ID
-	Error   *struct { // Possible error
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"error"`
-	Result *T `json:"result"`
+	Version string `json:"jsonrpc"` // 2.0
+	ID      uint   `json:"id"`      // Request ID
+	Error   *Error `json:"error"`   // Possible error
+	Result  *T     `json:"result"`
+}
+
+// Error : Error returned by a JSON-RPC call.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("request error %d: %s", e.Code, e.Message)
+}
+
+// SyncStatus : Result of optimism_syncStatus, irrelevant fields are ignored.
+type SyncStatus struct {
+	HeadL1   BlockRef `json:"head_l1"` // For check if sequencer is ready to be activated ( 12s * 3 )
+	UnsafeL2 BlockRef `json:"unsafe_l2"`
+
+	SafeL2      BlockRef `json:"safe_l2"`
+	FinalizedL2 BlockRef `json:"finalized_l2"`
+}
+
+// PeerDump : Result of opp2p_peers, irrelevant fields are ignored.
+type PeerDump struct {
+	TotalConnected int `json:"totalConnected"`
+}
+
+type BlockRef struct {
+	Hash       string `json:"hash"`
+	Number     int64  `json:"number"`
+	ParentHash string `json:"parentHash"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
+// Time : Timestamp of the block.
+func (b BlockRef) Time() time.Time {
+	return time.Unix(b.Timestamp, 0)
+}
+
+// L1HeadLag : How far the L1 head of the sequencer is behind now.
+func (s *SyncStatus) L1HeadLag() time.Duration {
+	return time.Since(time.Unix(s.HeadL1.Timestamp, 0))
+}
+
+// IsReady : Whether the sequencer is sync with mainnet (max tolerance 3 blocks behind) and ready to be activated.
+func (s *SyncStatus) IsReady() bool {
+	return time.Now().Unix()-s.HeadL1.Timestamp < MaxMainnetBlockTimestampLateTolerance
+}
+
+// parseQuantity : Parse a hex encoded quantity of the execution client, e.g. 0x1b4.
+func parseQuantity(quantity string) (int64, error) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(quantity, "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %s: %w", quantity, err)
+	}
+
+	return n, nil
 }
@@ -0,0 +1,60 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+	"github.com/rss3-network/vsl-reconcile/pkg/kube"
+)
+
+// Discoverer discovers the op-node admin endpoints of sequencers.
+type Discoverer interface {
+	Discover(ctx context.Context) ([]string, error)
+	String() string
+}
+
+// Watcher is a Discoverer which also notices sequencer changes at runtime.
+type Watcher interface {
+	Discoverer
+	Watch(ctx context.Context, onChange func(endpoints []string)) error
+}
+
+// Prioritizer is a Discoverer which also reads the priority of sequencers, keyed by endpoint.
+type Prioritizer interface {
+	Discoverer
+	Priorities(ctx context.Context) (map[string]int, error)
+}
+
+// ExecutionDiscoverer is a Discoverer which also discovers the op-geth endpoint paired with each sequencer, keyed by endpoint.
+type ExecutionDiscoverer interface {
+	Discoverer
+	ExecutionEndpoints(ctx context.Context) (map[string]string, error)
+}
+
+// ExecutionEndpoint builds the op-geth endpoint paired with a sequencer from template,
+// where {scheme} and {host} are replaced with those of the sequencer endpoint.
+func ExecutionEndpoint(template string, sequencer string) (string, error) {
+	u, err := url.Parse(sequencer)
+	if err != nil {
+		return "", fmt.Errorf("invalid sequencer endpoint %s: %w", sequencer, err)
+	}
+
+	return strings.NewReplacer("{scheme}", u.Scheme, "{host}", u.Hostname()).Replace(template), nil
+}
+
+// New creates the discoverer configured by cfg.
+func New(cfg *config.Config) (Discoverer, error) {
+	if len(cfg.SequencersList) > 0 {
+		return NewStatic(cfg.SequencersList), nil
+	}
+
+	clientset, err := kube.Client()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize kubernetes client: %w", err)
+	}
+
+	return NewStatefulSet(clientset, cfg), nil
+}
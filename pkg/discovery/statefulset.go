@@ -0,0 +1,368 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// annotationPriority is the priority of a sequencer pod, higher is preferred
+	annotationPriority = "vsl.rss3.io/priority"
+
+	// informerResyncPeriod is how often the informers re-deliver all objects, in case an event was missed
+	informerResyncPeriod = 10 * time.Minute
+)
+
+var (
+	_ Discoverer          = (*StatefulSet)(nil)
+	_ Watcher             = (*StatefulSet)(nil)
+	_ Prioritizer         = (*StatefulSet)(nil)
+	_ ExecutionDiscoverer = (*StatefulSet)(nil)
+)
+
+// StatefulSet discovers the pods of a sequencer StatefulSet through its headless service.
+type StatefulSet struct {
+	clientset *kubernetes.Clientset
+	name      string
+	namespace string
+
+	scheme        string
+	portName      string
+	port          int
+	clusterDomain string
+
+	// executionPortName is the port of the op-geth sidecar in the pod template
+	executionPortName string
+}
+
+func NewStatefulSet(clientset *kubernetes.Clientset, cfg *config.Config) *StatefulSet {
+	return &StatefulSet{
+		clientset:     clientset,
+		name:          cfg.DiscoverySTS,
+		namespace:     cfg.DiscoveryNS,
+		scheme:        cfg.DiscoveryScheme,
+		portName:      cfg.DiscoveryPortName,
+		port:          cfg.DiscoveryPort,
+		clusterDomain: cfg.ClusterDomain,
+
+		executionPortName: cfg.ExecutionPortName,
+	}
+}
+
+func (d *StatefulSet) Discover(ctx context.Context) ([]string, error) {
+	sts, err := d.clientset.AppsV1().StatefulSets(d.namespace).Get(ctx, d.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	podSelector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statefulset selector: %w", err)
+	}
+
+	pods, err := d.clientset.CoreV1().Pods(d.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: podSelector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	podNames := make([]string, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		podNames = append(podNames, pod.Name)
+	}
+
+	return d.endpoints(sts, podNames), nil
+}
+
+// PodEndpoints maps the name of each StatefulSet pod to its endpoint.
+func (d *StatefulSet) PodEndpoints(ctx context.Context) (map[string]string, error) {
+	sts, err := d.clientset.AppsV1().StatefulSets(d.namespace).Get(ctx, d.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	podEndpoints := make(map[string]string, int(*sts.Spec.Replicas))
+
+	for i := 0; i < int(*sts.Spec.Replicas); i++ {
+		podName := fmt.Sprintf("%s-%d", sts.Name, i)
+		podEndpoints[podName] = d.endpoint(sts, podName)
+	}
+
+	return podEndpoints, nil
+}
+
+// Priorities reads the priority annotation of the StatefulSet pods, keyed by endpoint.
+func (d *StatefulSet) Priorities(ctx context.Context) (map[string]int, error) {
+	sts, err := d.clientset.AppsV1().StatefulSets(d.namespace).Get(ctx, d.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	podSelector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statefulset selector: %w", err)
+	}
+
+	pods, err := d.clientset.CoreV1().Pods(d.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: podSelector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return d.priorities(sts, pods.Items), nil
+}
+
+// ExecutionEndpoints maps the endpoint of each StatefulSet pod to its op-geth sidecar, if the pod template has one.
+func (d *StatefulSet) ExecutionEndpoints(ctx context.Context) (map[string]string, error) {
+	sts, err := d.clientset.AppsV1().StatefulSets(d.namespace).Get(ctx, d.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return d.executionEndpoints(sts), nil
+}
+
+// Watch watches the StatefulSet and its pods, and calls onChange whenever the sequencers change.
+// Only pods which have been created are reported, so scaling is noticed as it happens. Terminating pods, including
+// those left over from a scale-down, are reported until they are deleted, as a primary being deleted may still be
+// sequencing. Nothing is reported before the informer caches are synced, so onChange never sees a partial pod list.
+func (d *StatefulSet) Watch(ctx context.Context, onChange func(endpoints []string)) error {
+	log := zap.L().With(zap.String("discovery", d.String()))
+
+	sts, err := d.clientset.AppsV1().StatefulSets(d.namespace).Get(ctx, d.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	podSelector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid statefulset selector: %w", err)
+	}
+
+	stsInformerFactory := informers.NewSharedInformerFactoryWithOptions(d.clientset, informerResyncPeriod,
+		informers.WithNamespace(d.namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", d.name).String()
+		}),
+	)
+	podInformerFactory := informers.NewSharedInformerFactoryWithOptions(d.clientset, informerResyncPeriod,
+		informers.WithNamespace(d.namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = podSelector.String()
+		}),
+	)
+
+	stsLister := stsInformerFactory.Apps().V1().StatefulSets().Lister()
+	podLister := podInformerFactory.Core().V1().Pods().Lister()
+
+	var (
+		mu       sync.Mutex
+		previous []string
+		synced   atomic.Bool
+	)
+
+	update := func() {
+		if !synced.Load() {
+			return
+		}
+
+		sts, err := stsLister.StatefulSets(d.namespace).Get(d.name)
+		if err != nil {
+			log.Error("failed to get statefulset", zap.Error(err))
+
+			return
+		}
+
+		pods, err := podLister.Pods(d.namespace).List(labels.Everything())
+		if err != nil {
+			log.Error("failed to list pods", zap.Error(err))
+
+			return
+		}
+
+		podNames := make([]string, 0, len(pods))
+		for _, pod := range pods {
+			podNames = append(podNames, pod.Name)
+		}
+
+		endpoints := d.endpoints(sts, podNames)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if slices.Equal(endpoints, previous) {
+			return
+		}
+
+		previous = endpoints
+
+		log.Info("sequencers changed", zap.Strings("sequencers", endpoints))
+		onChange(endpoints)
+	}
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(_ any) { update() },
+		UpdateFunc: func(_, _ any) { update() },
+		DeleteFunc: func(_ any) { update() },
+	}
+
+	if _, err = stsInformerFactory.Apps().V1().StatefulSets().Informer().AddEventHandler(handler); err != nil {
+		return fmt.Errorf("failed to watch statefulset: %w", err)
+	}
+
+	if _, err = podInformerFactory.Core().V1().Pods().Informer().AddEventHandler(handler); err != nil {
+		return fmt.Errorf("failed to watch pods: %w", err)
+	}
+
+	stsInformerFactory.Start(ctx.Done())
+	podInformerFactory.Start(ctx.Done())
+
+	if cache.WaitForCacheSync(ctx.Done(),
+		stsInformerFactory.Apps().V1().StatefulSets().Informer().HasSynced,
+		podInformerFactory.Core().V1().Pods().Informer().HasSynced,
+	) {
+		synced.Store(true)
+		update()
+	}
+
+	<-ctx.Done()
+
+	stsInformerFactory.Shutdown()
+	podInformerFactory.Shutdown()
+
+	return nil
+}
+
+func (d *StatefulSet) String() string {
+	return "statefulset"
+}
+
+// endpoints builds the endpoint of every existing pod of the StatefulSet, ordered by ordinal.
+// Pods with an ordinal at or above the replicas are left over from a scale-down, and are kept until they are deleted
+func (d *StatefulSet) endpoints(sts *appsv1.StatefulSet, podNames []string) []string {
+	var ordinals []int
+
+	for _, podName := range podNames {
+		if ordinal, ok := podOrdinal(sts, podName); ok {
+			ordinals = append(ordinals, ordinal)
+		}
+	}
+
+	slices.Sort(ordinals)
+
+	var endpoints []string
+
+	for _, ordinal := range slices.Compact(ordinals) {
+		endpoints = append(endpoints, d.endpoint(sts, fmt.Sprintf("%s-%d", sts.Name, ordinal)))
+	}
+
+	return endpoints
+}
+
+// podOrdinal parses the ordinal from the name of a StatefulSet pod, other pods matching the selector are ignored
+func podOrdinal(sts *appsv1.StatefulSet, podName string) (int, bool) {
+	suffix, ok := strings.CutPrefix(podName, sts.Name+"-")
+	if !ok {
+		return 0, false
+	}
+
+	ordinal, err := strconv.Atoi(suffix)
+
+	return ordinal, err == nil && ordinal >= 0
+}
+
+// priorities maps the endpoint of every annotated pod to its priority, invalid annotations are ignored
+func (d *StatefulSet) priorities(sts *appsv1.StatefulSet, pods []corev1.Pod) map[string]int {
+	priorities := make(map[string]int)
+
+	for _, pod := range pods {
+		value, ok := pod.Annotations[annotationPriority]
+		if !ok {
+			continue
+		}
+
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			zap.L().Warn("invalid priority annotation", zap.String("discovery", d.String()), zap.String("pod", pod.Name), zap.String("value", value))
+
+			continue
+		}
+
+		priorities[d.endpoint(sts, pod.Name)] = priority
+	}
+
+	return priorities
+}
+
+// executionEndpoints maps the endpoint of every replica to its op-geth sidecar, empty if the pod template has none
+func (d *StatefulSet) executionEndpoints(sts *appsv1.StatefulSet) map[string]string {
+	executionEndpoints := make(map[string]string)
+
+	port, ok := d.namedPort(sts, d.executionPortName)
+	if !ok {
+		return executionEndpoints
+	}
+
+	for i := 0; i < int(*sts.Spec.Replicas); i++ {
+		podName := fmt.Sprintf("%s-%d", sts.Name, i)
+		executionEndpoints[d.endpoint(sts, podName)] = d.podEndpoint(sts, podName, port)
+	}
+
+	return executionEndpoints
+}
+
+// endpoint builds the headless service pod dns of a pod
+func (d *StatefulSet) endpoint(sts *appsv1.StatefulSet, podName string) string {
+	return d.podEndpoint(sts, podName, d.containerPort(sts))
+}
+
+// podEndpoint builds the headless service pod dns of a pod with port
+func (d *StatefulSet) podEndpoint(sts *appsv1.StatefulSet, podName string, port int) string {
+	// get headless service
+	svcName := sts.Spec.ServiceName
+
+	return fmt.Sprintf("%s://%s.%s.%s.svc.%s:%d",
+		d.scheme, podName, svcName, sts.Namespace, d.clusterDomain, port,
+	)
+}
+
+// containerPort finds the named port in the pod template, the configured port is used if there is none
+func (d *StatefulSet) containerPort(sts *appsv1.StatefulSet) int {
+	if port, ok := d.namedPort(sts, d.portName); ok {
+		return port
+	}
+
+	return d.port
+}
+
+// namedPort finds the port named name in any container of the pod template
+func (d *StatefulSet) namedPort(sts *appsv1.StatefulSet, name string) (int, bool) {
+	for _, container := range sts.Spec.Template.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == name {
+				return int(port.ContainerPort), true
+			}
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,236 @@
+package discovery
+
+import (
+	"slices"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStsEndpoints(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(3)
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sequencer",
+			Namespace: "vsl",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: "sequencer-headless",
+		},
+	}
+
+	d := &StatefulSet{
+		scheme:        "http",
+		portName:      "rpc",
+		port:          9545,
+		clusterDomain: "cluster.local",
+	}
+
+	// Situation 1: All pods exist
+	endpoints := d.endpoints(sts, []string{"sequencer-2", "sequencer-0", "sequencer-1"})
+
+	expected := []string{
+		"http://sequencer-0.sequencer-headless.vsl.svc.cluster.local:9545",
+		"http://sequencer-1.sequencer-headless.vsl.svc.cluster.local:9545",
+		"http://sequencer-2.sequencer-headless.vsl.svc.cluster.local:9545",
+	}
+
+	if !slices.Equal(endpoints, expected) {
+		t.Log("endpoints mismatch", endpoints)
+
+		t.Fail()
+	}
+
+	// Situation 2: Pod 1 is not created yet
+	endpoints = d.endpoints(sts, []string{"sequencer-0", "sequencer-2"})
+
+	if !slices.Equal(endpoints, []string{expected[0], expected[2]}) {
+		t.Log("endpoints mismatch", endpoints)
+
+		t.Fail()
+	}
+
+	// Situation 3: Other pods match the selector, should be ignored
+	endpoints = d.endpoints(sts, []string{"sequencer-0", "sequencer-canary-0", "sequencer-1", "sequencer-2", "op-geth-0"})
+
+	if !slices.Equal(endpoints, expected) {
+		t.Log("endpoints mismatch", endpoints)
+
+		t.Fail()
+	}
+
+	// Situation 4: The pod template names the port, with a custom scheme and cluster domain
+	sts.Spec.Template.Spec.Containers = []corev1.Container{
+		{
+			Name: "op-node",
+			Ports: []corev1.ContainerPort{
+				{Name: "metrics", ContainerPort: 7300},
+				{Name: "rpc", ContainerPort: 8547},
+			},
+		},
+	}
+
+	d.scheme = "https"
+	d.clusterDomain = "vsl.internal"
+
+	endpoints = d.endpoints(sts, []string{"sequencer-0"})
+
+	if endpoints[0] != "https://sequencer-0.sequencer-headless.vsl.svc.vsl.internal:8547" {
+		t.Log("endpoints mismatch", endpoints)
+
+		t.Fail()
+	}
+}
+
+func TestStsScaleDown(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(2)
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sequencer",
+			Namespace: "vsl",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: "sequencer-headless",
+		},
+	}
+
+	d := &StatefulSet{
+		scheme:        "http",
+		portName:      "rpc",
+		port:          9545,
+		clusterDomain: "cluster.local",
+	}
+
+	expected := []string{
+		"http://sequencer-0.sequencer-headless.vsl.svc.cluster.local:9545",
+		"http://sequencer-1.sequencer-headless.vsl.svc.cluster.local:9545",
+		"http://sequencer-2.sequencer-headless.vsl.svc.cluster.local:9545",
+	}
+
+	// Condition 1: Scaled down from 3 to 2 replicas, the primary sequencer-2 is the highest ordinal
+
+	// Situation 1: Pod 2 is terminating, should keep the primary until it is deleted
+	endpoints := d.endpoints(sts, []string{"sequencer-0", "sequencer-1", "sequencer-2"})
+
+	if !slices.Equal(endpoints, expected) {
+		t.Log("endpoints mismatch", endpoints)
+
+		t.Fail()
+	}
+
+	// Situation 2: Pod 2 is deleted, should drop it
+	endpoints = d.endpoints(sts, []string{"sequencer-0", "sequencer-1"})
+
+	if !slices.Equal(endpoints, expected[:2]) {
+		t.Log("endpoints mismatch", endpoints)
+
+		t.Fail()
+	}
+}
+
+func TestStsPriorities(t *testing.T) {
+	t.Parallel()
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sequencer",
+			Namespace: "vsl",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			ServiceName: "sequencer-headless",
+		},
+	}
+
+	d := &StatefulSet{
+		scheme:        "http",
+		portName:      "rpc",
+		port:          9545,
+		clusterDomain: "cluster.local",
+	}
+
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "sequencer-0", Annotations: map[string]string{annotationPriority: "10"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "sequencer-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "sequencer-2", Annotations: map[string]string{annotationPriority: "high"}}},
+	}
+
+	// Situation 1: Only valid annotations are read
+	priorities := d.priorities(sts, pods)
+
+	if len(priorities) != 1 || priorities["http://sequencer-0.sequencer-headless.vsl.svc.cluster.local:9545"] != 10 {
+		t.Log("priorities mismatch", priorities)
+
+		t.Fail()
+	}
+}
+
+func TestStsExecutionEndpoints(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(2)
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sequencer",
+			Namespace: "vsl",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: "sequencer-headless",
+		},
+	}
+
+	d := &StatefulSet{
+		scheme:            "http",
+		portName:          "rpc",
+		port:              9545,
+		clusterDomain:     "cluster.local",
+		executionPortName: "geth-rpc",
+	}
+
+	// Situation 1: The pod template has no op-geth sidecar
+	if executionEndpoints := d.executionEndpoints(sts); len(executionEndpoints) != 0 {
+		t.Log("execution endpoints should be empty", executionEndpoints)
+
+		t.Fail()
+	}
+
+	// Situation 2: op-geth runs as a sidecar
+	sts.Spec.Template.Spec.Containers = []corev1.Container{
+		{
+			Name:  "op-node",
+			Ports: []corev1.ContainerPort{{Name: "rpc", ContainerPort: 9545}},
+		},
+		{
+			Name:  "op-geth",
+			Ports: []corev1.ContainerPort{{Name: "geth-rpc", ContainerPort: 8545}},
+		},
+	}
+
+	executionEndpoints := d.executionEndpoints(sts)
+
+	if executionEndpoints["http://sequencer-1.sequencer-headless.vsl.svc.cluster.local:9545"] != "http://sequencer-1.sequencer-headless.vsl.svc.cluster.local:8545" {
+		t.Log("execution endpoints mismatch", executionEndpoints)
+
+		t.Fail()
+	}
+
+	// Situation 3: The op-geth endpoint is built from a template
+	executionEndpoint, err := ExecutionEndpoint("{scheme}://{host}:8545", "https://sequencer-0.vsl.internal:9545")
+
+	if err != nil || executionEndpoint != "https://sequencer-0.vsl.internal:8545" {
+		t.Log("execution endpoint mismatch", executionEndpoint, err)
+
+		t.Fail()
+	}
+}
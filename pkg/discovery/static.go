@@ -0,0 +1,26 @@
+package discovery
+
+import (
+	"context"
+)
+
+var _ Discoverer = (*Static)(nil)
+
+// Static discovers a fixed list of sequencers, for running outside Kubernetes.
+type Static struct {
+	endpoints []string
+}
+
+func NewStatic(endpoints []string) *Static {
+	return &Static{
+		endpoints: endpoints,
+	}
+}
+
+func (d *Static) Discover(_ context.Context) ([]string, error) {
+	return append([]string(nil), d.endpoints...), nil
+}
+
+func (d *Static) String() string {
+	return "static"
+}
@@ -0,0 +1,72 @@
+package dryrun
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxPlanSize is the number of most recent actions kept in the plan
+const maxPlanSize = 1000
+
+const (
+	KindRPC  = "rpc"
+	KindKube = "kube"
+	KindFile = "file"
+)
+
+// Action is a mutation which would have been performed if not in dry-run mode.
+type Action struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Target string    `json:"target"`
+	Method string    `json:"method"`
+	Params []string  `json:"params,omitempty"`
+}
+
+var (
+	enabled atomic.Bool
+
+	mu   sync.Mutex
+	plan []Action
+)
+
+// Enable turns on dry-run mode, in which sequencers and pods are never mutated.
+func Enable() {
+	enabled.Store(true)
+}
+
+// Enabled reports whether dry-run mode is on.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Record adds an action to the plan.
+func Record(action Action) {
+	action.Time = time.Now()
+
+	zap.L().Info("dry-run: planned action",
+		zap.String("kind", action.Kind),
+		zap.String("target", action.Target),
+		zap.String("method", action.Method),
+		zap.Strings("params", action.Params),
+	)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	plan = append(plan, action)
+	if len(plan) > maxPlanSize {
+		plan = plan[len(plan)-maxPlanSize:]
+	}
+}
+
+// Plan returns the recorded actions, oldest first.
+func Plan() []Action {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return append([]Action(nil), plan...)
+}
@@ -0,0 +1,34 @@
+package dryrun
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	// Situation 1: Should keep the most recent actions only
+	for i := 0; i < maxPlanSize+10; i++ {
+		Record(Action{
+			Kind:   KindRPC,
+			Target: "sequencer-" + strconv.Itoa(i),
+			Method: "admin_stopSequencer",
+		})
+	}
+
+	actions := Plan()
+
+	if len(actions) != maxPlanSize {
+		t.Log("plan size is incorrect", len(actions))
+
+		t.Fail()
+	}
+
+	if actions[0].Target != "sequencer-10" || actions[len(actions)-1].Target != "sequencer-"+strconv.Itoa(maxPlanSize+9) {
+		t.Log("plan order is incorrect", actions[0].Target, actions[len(actions)-1].Target)
+
+		t.Fail()
+	}
+
+}
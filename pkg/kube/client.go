@@ -6,6 +6,10 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/rss3-network/vsl-reconcile/pkg/dryrun"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -43,7 +47,100 @@ func Client() (*kubernetes.Clientset, error) {
 func PatchPod(ctx context.Context, clientset *kubernetes.Clientset, namespace, name, key, value string) error {
 	patch := fmt.Sprintf(`{"metadata":{"labels":{"%s":"%s"}}}`, key, value)
 
+	if dryrun.Enabled() {
+		dryrun.Record(dryrun.Action{
+			Kind:   dryrun.KindKube,
+			Target: fmt.Sprintf("%s/%s", namespace, name),
+			Method: "patch",
+			Params: []string{patch},
+		})
+
+		return nil
+	}
+
 	_, err := clientset.CoreV1().Pods(namespace).Patch(ctx, name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
 
 	return err
 }
+
+// StatefulSetAnnotation gets an annotation of a StatefulSet, empty if it is not set.
+func StatefulSetAnnotation(ctx context.Context, clientset *kubernetes.Clientset, namespace, name, key string) (string, error) {
+	sts, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	return sts.Annotations[key], nil
+}
+
+// ConfigMapData gets a key of a ConfigMap, empty if the ConfigMap or the key does not exist.
+func ConfigMapData(ctx context.Context, clientset *kubernetes.Clientset, namespace, name, key string) (string, error) {
+	cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return cm.Data[key], nil
+}
+
+// SetConfigMapData sets a key of a ConfigMap, which is created if it does not exist.
+func SetConfigMapData(ctx context.Context, clientset *kubernetes.Clientset, namespace, name, key, value string) error {
+	if dryrun.Enabled() {
+		dryrun.Record(dryrun.Action{
+			Kind:   dryrun.KindKube,
+			Target: fmt.Sprintf("%s/%s", namespace, name),
+			Method: "set",
+			Params: []string{key, value},
+		})
+
+		return nil
+	}
+
+	configMaps := clientset.CoreV1().ConfigMaps(namespace)
+
+	cm, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Data: map[string]string{key: value},
+		}, metav1.CreateOptions{})
+
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+
+	cm.Data[key] = value
+
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+
+	return err
+}
+
+// SecretData gets a key of a Secret.
+func SecretData(ctx context.Context, clientset *kubernetes.Clientset, namespace, name, key string) ([]byte, error) {
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no key %s", namespace, name, key)
+	}
+
+	return data, nil
+}
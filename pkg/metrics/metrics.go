@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "vsl_reconcile"
+
+var (
+	// PrimarySequencerID is the ID of the sequencer currently believed to be primary, -1 if none.
+	PrimarySequencerID = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "primary_sequencer_id",
+		Help:      "ID of the current primary sequencer, -1 if there is none.",
+	})
+
+	// UnsafeL2Height is the unsafe L2 block number reported by each sequencer.
+	UnsafeL2Height = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sequencer_unsafe_l2_height",
+		Help:      "Unsafe L2 block number reported by the sequencer.",
+	}, []string{"sequencer"})
+
+	// L1HeadLag is how far behind the L1 head of each sequencer is.
+	L1HeadLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sequencer_l1_head_lag_seconds",
+		Help:      "Seconds between now and the L1 head timestamp reported by the sequencer.",
+	}, []string{"sequencer"})
+
+	// SecondsSinceLastBlock is the time since the primary sequencer produced a new block.
+	SecondsSinceLastBlock = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "seconds_since_last_block",
+		Help:      "Seconds since a new block was produced by the primary sequencer.",
+	})
+
+	// Maintenance is 1 while automatic failover is paused.
+	Maintenance = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "maintenance",
+		Help:      "Whether automatic failover is paused for maintenance.",
+	})
+
+	// Phase is 1 for the current phase of the heartbeat loop and 0 for the others.
+	Phase = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "phase",
+		Help:      "Current phase of the heartbeat loop, 1 for the current phase.",
+	}, []string{"phase"})
+
+	// DegradedRetries is the number of promotions retried while no sequencer could be activated.
+	DegradedRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "degraded_retries_total",
+		Help:      "Number of promotions retried while no sequencer could be activated.",
+	})
+
+	// SwitchoversDeferred is the number of automatic switchovers deferred by flap damping, by reason.
+	SwitchoversDeferred = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "switchovers_deferred_total",
+		Help:      "Number of automatic switchovers deferred by the cooldown or the switchover budget.",
+	}, []string{"reason"})
+
+	// Quarantined is 1 while a sequencer is quarantined after it failed to activate.
+	Quarantined = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sequencer_quarantined",
+		Help:      "Whether the sequencer is quarantined after it failed to activate.",
+	}, []string{"sequencer"})
+
+	// SafeLag is how many blocks the safe head of a sequencer is behind its unsafe head.
+	SafeLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sequencer_safe_lag_blocks",
+		Help:      "Number of blocks the safe L2 head of the sequencer is behind its unsafe L2 head.",
+	}, []string{"sequencer"})
+
+	// SecondsSinceSafeHead is how long the highest safe head of the sequencers has not advanced.
+	SecondsSinceSafeHead = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "seconds_since_safe_head",
+		Help:      "Seconds since the highest safe L2 head of the sequencers advanced.",
+	})
+
+	// SafeHeadStalled is 1 while the safe head has not advanced for the stall timeout.
+	SafeHeadStalled = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "safe_head_stalled",
+		Help:      "Whether the safe L2 head has not advanced for the stall timeout.",
+	})
+
+	// ExecutionHeight is the latest block number of the op-geth paired with a sequencer.
+	ExecutionHeight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sequencer_execution_height",
+		Help:      "Latest block number of the op-geth paired with the sequencer.",
+	}, []string{"sequencer"})
+
+	// HealthScore is the aggregate health score of a sequencer, from 0 (unhealthy) to 1 (healthy).
+	HealthScore = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sequencer_health_score",
+		Help:      "Aggregate health score of the sequencer, from 0 (unhealthy) to 1 (healthy).",
+	}, []string{"sequencer"})
+
+	// HealthCheckScore is the score of a single health check of a sequencer.
+	HealthCheckScore = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sequencer_health_check_score",
+		Help:      "Score of a single health check of the sequencer, from 0 (unhealthy) to 1 (healthy).",
+	}, []string{"sequencer", "check"})
+
+	// SplitBrainIncidents is the number of times more than one sequencer was found active.
+	SplitBrainIncidents = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "split_brain_incidents_total",
+		Help:      "Number of times more than one sequencer was found active.",
+	})
+
+	// SwitchoverAttempts is the number of times block production was handed over from the primary sequencer.
+	SwitchoverAttempts = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "switchover_attempts_total",
+		Help:      "Number of attempted primary sequencer switchovers.",
+	})
+
+	// SwitchoverSuccesses is the number of switchovers which activated another sequencer.
+	SwitchoverSuccesses = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "switchover_successes_total",
+		Help:      "Number of successful primary sequencer switchovers.",
+	})
+
+	// SwitchoverFailures is the number of switchovers which could not activate any sequencer.
+	SwitchoverFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "switchover_failures_total",
+		Help:      "Number of failed primary sequencer switchovers.",
+	})
+
+	// RPCDuration is the latency of each JSON-RPC call attempt.
+	RPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rpc_duration_seconds",
+		Help:      "Latency of JSON-RPC calls to sequencers.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	// RPCErrors is the number of failed JSON-RPC call attempts.
+	RPCErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_errors_total",
+		Help:      "Number of failed JSON-RPC calls to sequencers.",
+	}, []string{"method"})
+)
@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+)
+
+const (
+	// EventDegraded is sent when no sequencer could be activated and the chain is left without a primary
+	EventDegraded = "degraded"
+	// EventRecovered is sent when a primary sequencer is activated again after being degraded
+	EventRecovered = "recovered"
+	// EventSplitBrain is sent when more than one sequencer was active and the extra ones were fenced
+	EventSplitBrain = "split_brain"
+	// EventSafeHeadStalled is sent when the safe head stops advancing, which usually means op-batcher is broken
+	EventSafeHeadStalled = "safe_head_stalled"
+	// EventSafeHeadRecovered is sent when the safe head advances again after it stalled
+	EventSafeHeadRecovered = "safe_head_recovered"
+)
+
+// Event is a notable change of the sequencer cluster.
+type Event struct {
+	Event string    `json:"event"`
+	Time  time.Time `json:"time"`
+
+	// Text is a human readable summary, which is also shown by Slack compatible webhooks
+	Text string `json:"text"`
+
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Notifier sends events to operators.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+	String() string
+}
+
+// New creates the notifier configured by cfg, nil if notifications are disabled.
+func New(cfg *config.Config) Notifier {
+	if cfg.NotifyWebhookURL == "" {
+		return nil
+	}
+
+	return NewWebhook(cfg.NotifyWebhookURL)
+}
@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhook(t *testing.T) {
+	t.Parallel()
+
+	events := make(chan Event, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if event.Event == EventRecovered {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		events <- event
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL)
+
+	// Situation 1: The event is posted as JSON
+	err := webhook.Notify(context.Background(), Event{
+		Event:  EventDegraded,
+		Time:   time.Now(),
+		Text:   "No sequencer could be activated",
+		Fields: map[string]string{"sequencers": "3"},
+	})
+	if err != nil {
+		t.Fatal("failed to notify", err)
+	}
+
+	event := <-events
+
+	if event.Event != EventDegraded || event.Text != "No sequencer could be activated" || event.Fields["sequencers"] != "3" {
+		t.Log("event mismatch", event)
+
+		t.Fail()
+	}
+
+	// Situation 2: The webhook rejects the event
+	if err := webhook.Notify(context.Background(), Event{Event: EventRecovered}); err == nil {
+		t.Log("should fail on error status")
+
+		t.Fail()
+	}
+}
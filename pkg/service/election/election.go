@@ -0,0 +1,148 @@
+package election
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+	"github.com/rss3-network/vsl-reconcile/internal/safe"
+	"github.com/rss3-network/vsl-reconcile/pkg/dryrun"
+	"github.com/rss3-network/vsl-reconcile/pkg/kube"
+	"github.com/rss3-network/vsl-reconcile/pkg/service"
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/leaderelection"
+)
+
+var _ service.Service = (*Service)(nil)
+
+// Service runs the wrapped services only on the replica holding the leader lease,
+// so that multiple reconcile replicas never act on the sequencers at the same time.
+type Service struct {
+	services []service.Service
+
+	identity string
+	elector  *leaderelection.LeaderElector // nil if leader election is disabled
+	pool     *safe.Pool
+	leading  atomic.Bool
+}
+
+func New(services ...service.Service) *Service {
+	return &Service{
+		services: services,
+	}
+}
+
+func (s *Service) Run(pool *safe.Pool) error {
+	if s.elector == nil {
+		s.runServices(pool)
+
+		return nil
+	}
+
+	s.pool = pool
+
+	pool.GoCtx(func(ctx context.Context) {
+		s.elector.Run(ctx)
+	})
+
+	return nil
+}
+
+func (s *Service) Init(cfg *config.Config) error {
+	log := zap.L().With(zap.String("service", s.String()))
+
+	var services []service.Service
+
+	for _, svc := range s.services {
+		log.Info("init service", zap.String("service", svc.String()))
+
+		if err := svc.Init(cfg); err != nil {
+			log.Error("failed to add service", zap.Error(err), zap.String("service", svc.String()))
+
+			continue
+		}
+
+		services = append(services, svc)
+	}
+
+	s.services = services
+	s.identity = cfg.LeaderElection.Identity
+
+	if !cfg.LeaderElection.Enabled {
+		return nil
+	}
+
+	// A dry-run replica must never take the lease from the replica in control
+	if dryrun.Enabled() {
+		log.Warn("leader election is disabled in dry-run mode")
+
+		return nil
+	}
+
+	clientset, err := kube.Client()
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubernetes client: %w", err)
+	}
+
+	s.elector, err = kube.NewLeaderElector(clientset, cfg.LeaderElection, leaderelection.LeaderCallbacks{
+		OnStartedLeading: func(_ context.Context) {
+			log.Info("started leading", zap.String("identity", s.identity))
+			s.leading.Store(true)
+			s.runServices(s.pool)
+		},
+		OnStoppedLeading: func() {
+			if !s.leading.Load() {
+				return
+			}
+
+			// Running loops can not be interrupted safely, so exit and let a standby take over
+			log.Fatal("stopped leading", zap.String("identity", s.identity))
+		},
+		OnNewLeader: func(identity string) {
+			log.Info("new leader elected", zap.String("leader", identity))
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize leader elector: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) String() string {
+	return "election"
+}
+
+// Identity returns the identity of this replica.
+func (s *Service) Identity() string {
+	return s.identity
+}
+
+// Leader returns the identity of the current leader.
+func (s *Service) Leader() string {
+	if s.elector == nil {
+		return s.identity
+	}
+
+	return s.elector.GetLeader()
+}
+
+// IsLeader reports whether this replica is the current leader.
+func (s *Service) IsLeader() bool {
+	if s.elector == nil {
+		return true
+	}
+
+	return s.elector.IsLeader()
+}
+
+func (s *Service) runServices(pool *safe.Pool) {
+	for _, svc := range s.services {
+		zap.L().Info("start service", zap.String("service", svc.String()))
+
+		if err := svc.Run(pool); err != nil {
+			zap.L().Error("service failed", zap.Error(err), zap.String("service", svc.String()))
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestCheckBlockHeight(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 1)
+
+	log := zap.NewNop()
+
+	// Condition 1: 0 is primary, all is ready
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(i == 0)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+	}
+
+	s.setPrimary(0)
+
+	// Situation 1: New block produced just now, should keep 0
+	blockTime := time.Now().Truncate(time.Second)
+
+	sequencers[0].SetUnsafeBlock(10, blockTime)
+
+	blockHeight, currentBlockTime, err := s.checkBlockHeight(context.Background(), 0, log, 0, s.currentBlockTime.Add(-time.Hour))
+
+	if err != nil || blockHeight != 10 || !currentBlockTime.Equal(blockTime) {
+		t.Log("block height or time mismatch", blockHeight, currentBlockTime, err)
+
+		t.Fail()
+	}
+
+	// Situation 2: Primary was set just now, the block time should not be before it even if the head is old
+	sequencers[0].SetUnsafeBlock(10, time.Now().Add(-time.Hour))
+
+	setTime := time.Now()
+
+	_, currentBlockTime, err = s.checkBlockHeight(context.Background(), 0, log, 10, setTime)
+
+	if err != nil || !currentBlockTime.Equal(setTime) {
+		t.Log("block time mismatch", currentBlockTime, err)
+
+		t.Fail()
+	}
+
+	// Situation 3: No block produced since, should report the head timestamp
+	blockTime = time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	sequencers[0].SetUnsafeBlock(10, blockTime)
+
+	blockHeight, currentBlockTime, err = s.checkBlockHeight(context.Background(), 0, log, 10, blockTime.Add(-time.Minute))
+
+	if err != nil || blockHeight != 10 || !currentBlockTime.Equal(blockTime) {
+		t.Log("block height or time mismatch", blockHeight, currentBlockTime, err)
+
+		t.Fail()
+	}
+}
@@ -0,0 +1,107 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestBootstrapRetry(t *testing.T) {
+	t.Parallel()
+
+	base, sequencers := newTestService(t, 2)
+
+	log := zap.NewNop()
+
+	newService := func(bootstrapTimeout time.Duration) *Service {
+		s := &Service{
+			client:             base.client,
+			sequencerList:      base.sequencerList,
+			primarySequencerID: -1,
+			bootstrapTimeout:   bootstrapTimeout,
+			degradedBackoff:    10 * time.Millisecond,
+			degradedMaxBackoff: 20 * time.Millisecond,
+		}
+
+		s.phase.Store(int32(PhaseBootstrapping))
+
+		return s
+	}
+
+	// Condition 1: No sequencer is ready
+	for _, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(false)
+
+		ms.SetIsReady(false)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+	}
+
+	// Situation 1: Should give up after the bootstrap timeout and be degraded
+	s := newService(50 * time.Millisecond)
+
+	s.bootstrap(context.Background(), log)
+
+	if s.Phase() != PhaseDegraded || s.PrimarySequencerID() != -1 {
+		t.Log("should be degraded", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 2: Should keep retrying without a bootstrap timeout until 1 becomes ready
+	s = newService(0)
+
+	done := make(chan struct{})
+
+	go func() {
+		s.bootstrap(context.Background(), log)
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if s.Phase() != PhaseBootstrapping {
+		t.Log("should still be bootstrapping", s.Phase())
+
+		t.Fail()
+	}
+
+	sequencers[1].SetIsReady(true)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("bootstrap should finish once a sequencer is ready")
+	}
+
+	if s.Phase() != PhaseMonitoring || s.PrimarySequencerID() != 1 || !sequencers[1].GetIsActivated() {
+		t.Log("should promote 1", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Condition 2: The context is cancelled while bootstrapping
+	for _, ms := range sequencers {
+		ms.SetIsActivated(false)
+
+		ms.SetIsReady(false)
+	}
+
+	// Situation 1: Should stop bootstrapping
+	s = newService(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	s.bootstrap(ctx, log)
+
+	if s.Phase() != PhaseBootstrapping {
+		t.Log("should stop while bootstrapping", s.Phase())
+
+		t.Fail()
+	}
+}
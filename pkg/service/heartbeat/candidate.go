@@ -0,0 +1,148 @@
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/internal/rpc"
+	"go.uber.org/zap"
+)
+
+// handoffSyncPollInterval is how often a candidate is polled while waiting for it to sync the handed-off unsafe block
+const handoffSyncPollInterval = time.Second
+
+// rankCandidates polls the candidates concurrently, and orders the ready ones by how close their unsafe head is
+// to the handed-off unsafe block, or to the highest unsafe head if it is not known.
+// Candidates behind the handed-off unsafe block go last, as they have to catch up before they are activated,
+// candidates of equal distance keep their order, s.mu must be held
+func (s *Service) rankCandidates(ctx context.Context, candidates []int, unsafeHash string, log *zap.Logger) []int {
+	statuses := s.syncStatuses(ctx, candidates, log)
+
+	// The handed-off unsafe block is known if any sequencer, usually the previous primary, has it as its unsafe head
+	var (
+		target      int64
+		knownTarget bool
+	)
+
+	for _, syncStatus := range statuses {
+		if syncStatus == nil {
+			continue
+		}
+
+		if unsafeHash != "" && syncStatus.UnsafeL2.Hash == unsafeHash {
+			target, knownTarget = syncStatus.UnsafeL2.Number, true
+
+			break
+		}
+
+		target = max(target, syncStatus.UnsafeL2.Number)
+	}
+
+	ranked := make([]int, 0, len(candidates))
+	distances := make(map[int]int64, len(candidates))
+	behind := make(map[int]bool, len(candidates))
+
+	for i, id := range candidates {
+		syncStatus := statuses[i]
+
+		if syncStatus == nil || !syncStatus.IsReady() || syncStatus.UnsafeL2.Hash == "" {
+			continue
+		}
+
+		if knownTarget && syncStatus.UnsafeL2.Number < target {
+			log.Info("Candidate is behind the handed-off unsafe block",
+				zap.String("sequencer", s.sequencerList[id]),
+				zap.Int64("unsafe_l2_number", syncStatus.UnsafeL2.Number),
+				zap.Int64("handoff_number", target),
+			)
+
+			behind[id] = true
+		}
+
+		ranked = append(ranked, id)
+		distances[id] = abs(target - syncStatus.UnsafeL2.Number)
+	}
+
+	slices.SortStableFunc(ranked, func(a, b int) int {
+		if behind[a] != behind[b] {
+			if behind[a] {
+				return 1
+			}
+
+			return -1
+		}
+
+		return int(distances[a] - distances[b])
+	})
+
+	return ranked
+}
+
+// syncStatuses polls the sequencers concurrently, nil for those which could not be polled, s.mu must be held
+func (s *Service) syncStatuses(ctx context.Context, ids []int, log *zap.Logger) []*rpc.SyncStatus {
+	statuses := make([]*rpc.SyncStatus, len(ids))
+
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		wg.Add(1)
+
+		go func(i int, sequencer string) {
+			defer wg.Done()
+
+			syncStatus, err := s.client.GetSyncStatus(ctx, sequencer)
+			if err != nil {
+				s.recordError(sequencer, err)
+				log.Error("Failed to get sequencer sync status", zap.String("sequencer", sequencer), zap.Error(err))
+
+				return
+			}
+
+			statuses[i] = syncStatus
+		}(i, s.sequencerList[id])
+	}
+
+	wg.Wait()
+
+	return statuses
+}
+
+// waitForUnsafeBlock waits up to the handoff sync timeout for the unsafe head of sequencer to be the handed-off unsafe block,
+// as the sequencer is started on top of it
+func (s *Service) waitForUnsafeBlock(ctx context.Context, sequencer string, unsafeHash string) error {
+	deadline := time.Now().Add(s.handoffSyncTimeout)
+
+	for {
+		syncStatus, err := s.client.GetSyncStatus(ctx, sequencer)
+		if err != nil {
+			return err
+		}
+
+		if syncStatus.UnsafeL2.Hash == unsafeHash {
+			return nil
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return fmt.Errorf("sequencer %s has not synced the unsafe block %s, its unsafe head is %s (%d)",
+				sequencer, unsafeHash, syncStatus.UnsafeL2.Hash, syncStatus.UnsafeL2.Number)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(wait, handoffSyncPollInterval)):
+		}
+	}
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
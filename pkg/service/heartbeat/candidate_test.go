@@ -0,0 +1,141 @@
+package heartbeat
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestRankCandidates(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 4)
+
+	log := zap.NewNop()
+
+	// Condition 1: 0 is at block 8, 1 at block 10, 2 at block 9 and 3 is not ready
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsReady(i != 3)
+	}
+
+	sequencers[0].SetUnsafeHash("unsafe-hash-8")
+
+	sequencers[0].SetUnsafeBlock(8, time.Now())
+
+	sequencers[1].SetUnsafeHash("unsafe-hash-10")
+
+	sequencers[1].SetUnsafeBlock(10, time.Now())
+
+	sequencers[2].SetUnsafeHash("unsafe-hash-9")
+
+	sequencers[2].SetUnsafeBlock(9, time.Now())
+
+	sequencers[3].SetUnsafeHash("unsafe-hash-10")
+
+	sequencers[3].SetUnsafeBlock(10, time.Now())
+
+	// Situation 1: Without a handed-off unsafe block, the highest unsafe head goes first
+	if ranked := s.rankCandidates(context.Background(), []int{0, 1, 2, 3}, "", log); !slices.Equal(ranked, []int{1, 2, 0}) {
+		t.Log("ranked candidates mismatch", ranked)
+
+		t.Fail()
+	}
+
+	// Situation 2: Candidates behind the handed-off unsafe block go last
+	if ranked := s.rankCandidates(context.Background(), []int{0, 1, 2, 3}, "unsafe-hash-9", log); !slices.Equal(ranked, []int{2, 1, 0}) {
+		t.Log("ranked candidates mismatch", ranked)
+
+		t.Fail()
+	}
+
+	// Situation 3: Should activate the closest candidate
+	if id := s.activateSequencerByID(context.Background(), 0, ""); id != 1 || !sequencers[1].GetIsActivated() {
+		t.Log("should activate 1", id)
+
+		t.Fail()
+	}
+}
+
+func TestHandoffUnsafeBlock(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 1)
+
+	ms, endpoint := sequencers[0], s.sequencerList[0]
+
+	// Condition 1: The candidate has not synced the handed-off unsafe block
+	ms.SetIsWithAdmin(true)
+
+	ms.SetIsActivated(false)
+
+	ms.SetIsReady(true)
+
+	ms.SetUnsafeHash("unsafe-hash-1")
+
+	// Situation 1: Should not activate without waiting
+	if activated, err := s.activateSequencer(context.Background(), endpoint, "unsafe-hash-2"); activated || err == nil || ms.GetIsActivated() {
+		t.Log("should not activate a candidate without the handed-off unsafe block", err)
+
+		t.Fail()
+	}
+
+	// Situation 2: Should activate once the candidate catches up within the handoff sync timeout
+	s.handoffSyncTimeout = 5 * time.Second
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+
+		ms.SetUnsafeHash("unsafe-hash-2")
+	}()
+
+	if activated, err := s.activateSequencer(context.Background(), endpoint, "unsafe-hash-2"); !activated || err != nil || !ms.GetIsActivated() {
+		t.Log("should activate the candidate after it catches up", err)
+
+		t.Fail()
+	}
+}
+
+func TestSwitchSequencerHandsOffUnsafeBlock(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 3)
+
+	log := zap.NewNop()
+
+	// Condition 1: 0 is primary and stops at unsafe-hash-2, 1 has not synced it yet, 2 has
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(i == 0)
+
+		ms.SetIsReady(i != 0)
+
+		ms.SetUnsafeBlock(10, time.Now())
+	}
+
+	sequencers[0].SetUnsafeHash("unsafe-hash-2")
+
+	sequencers[1].SetUnsafeHash("unsafe-hash-1")
+
+	sequencers[2].SetUnsafeHash("unsafe-hash-2")
+
+	// Situation 1: Should hand off the unsafe block the primary stopped at, so only 2 can be activated
+	if id := s.switchSequencer(context.Background(), 0, log); id != 2 {
+		t.Log("should activate 2", id)
+
+		t.Fail()
+	}
+
+	for i, ms := range sequencers {
+		if ms.GetIsActivated() != (i == 2) {
+			t.Log("sequencer state is incorrect", i)
+
+			t.Fail()
+		}
+	}
+}
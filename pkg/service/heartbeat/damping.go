@@ -0,0 +1,135 @@
+package heartbeat
+
+import (
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// switchoverBudgetWindow is the window of the switchover budget
+const switchoverBudgetWindow = time.Hour
+
+// recordSwitchover counts a switchover against the budget, s.mu must be held
+func (s *Service) recordSwitchover(now time.Time, log *zap.Logger) {
+	s.lastSwitchover = now
+	s.switchovers = append(s.recentSwitchovers(now), now)
+
+	log.Info("Switchover recorded",
+		zap.String("event", "switchover"),
+		zap.Int("switchovers_last_hour", len(s.switchovers)),
+		zap.Int("max_switchovers_per_hour", s.maxSwitchovers),
+	)
+}
+
+// recentSwitchovers drops the switchovers which are out of the budget window, s.mu must be held
+func (s *Service) recentSwitchovers(now time.Time) []time.Time {
+	for len(s.switchovers) > 0 && now.Sub(s.switchovers[0]) >= switchoverBudgetWindow {
+		s.switchovers = s.switchovers[1:]
+	}
+
+	return s.switchovers
+}
+
+// cooldownUntil is when automatic switchovers are no longer deferred after the last switchover, s.mu must be held
+func (s *Service) cooldownUntil() time.Time {
+	return s.lastSwitchover.Add(s.switchoverCooldown)
+}
+
+// switchoverBudgetSpent reports whether the switchovers within the budget window used up the budget, s.mu must be held
+func (s *Service) switchoverBudgetSpent(now time.Time) bool {
+	return s.maxSwitchovers > 0 && len(s.recentSwitchovers(now)) >= s.maxSwitchovers
+}
+
+// deferSwitchover reports whether an automatic switchover away from the primary sequencer
+// has to wait for the cooldown or the switchover budget, s.mu must be held
+func (s *Service) deferSwitchover(log *zap.Logger) bool {
+	now := time.Now()
+
+	if cooldownUntil := s.cooldownUntil(); now.Before(cooldownUntil) {
+		metrics.SwitchoversDeferred.WithLabelValues("cooldown").Inc()
+
+		log.Warn("Switchover deferred",
+			zap.String("event", "switchover_deferred"),
+			zap.String("reason", "cooldown"),
+			zap.Time("cooldown_until", cooldownUntil),
+		)
+
+		return true
+	}
+
+	if s.switchoverBudgetSpent(now) {
+		metrics.SwitchoversDeferred.WithLabelValues("budget").Inc()
+
+		log.Error("Switchover deferred",
+			zap.String("event", "switchover_deferred"),
+			zap.String("reason", "budget"),
+			zap.Int("switchovers_last_hour", len(s.switchovers)),
+			zap.Int("max_switchovers_per_hour", s.maxSwitchovers),
+		)
+
+		return true
+	}
+
+	return false
+}
+
+// quarantine makes sequencer a last resort for the quarantine duration after it failed to activate, s.mu must be held
+func (s *Service) quarantine(sequencer string, err error, log *zap.Logger) {
+	if s.quarantineDuration == 0 {
+		return
+	}
+
+	if s.quarantined == nil {
+		s.quarantined = make(map[string]time.Time)
+	}
+
+	until := time.Now().Add(s.quarantineDuration)
+	s.quarantined[sequencer] = until
+
+	metrics.Quarantined.WithLabelValues(sequencer).Set(1)
+
+	log.Warn("Sequencer quarantined",
+		zap.String("event", "sequencer_quarantined"),
+		zap.String("sequencer", sequencer),
+		zap.Time("quarantined_until", until),
+		zap.Error(err),
+	)
+}
+
+// quarantinedUntil is when the quarantine of sequencer ends, s.mu must be held
+func (s *Service) quarantinedUntil(sequencer string) (time.Time, bool) {
+	until, ok := s.quarantined[sequencer]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	if time.Now().After(until) {
+		delete(s.quarantined, sequencer)
+
+		metrics.Quarantined.WithLabelValues(sequencer).Set(0)
+
+		return time.Time{}, false
+	}
+
+	return until, true
+}
+
+// deprioritizeQuarantined moves quarantined candidates to the end, so they are only tried after every other one, s.mu must be held
+func (s *Service) deprioritizeQuarantined(candidates []int) []int {
+	ordered := make([]int, 0, len(candidates))
+
+	var quarantined []int
+
+	for _, id := range candidates {
+		if _, ok := s.quarantinedUntil(s.sequencerList[id]); ok {
+			quarantined = append(quarantined, id)
+
+			continue
+		}
+
+		ordered = append(ordered, id)
+	}
+
+	return append(ordered, quarantined...)
+}
@@ -0,0 +1,301 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+	"go.uber.org/zap"
+)
+
+func TestDamping(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 3)
+
+	log := zap.NewNop()
+
+	health := config.Health{
+		Checks:         []string{config.HealthCheckPeers},
+		Policy:         config.HealthPolicyMin,
+		DegradedScore:  0.75,
+		UnhealthyScore: 0.25,
+		Peers:          config.Threshold{Warn: 3, Crit: 0},
+	}
+
+	s.health = health
+	s.healthChecks = newHealthChecks(health)
+	s.switchoverCooldown = time.Hour
+	s.maxSwitchovers = 2
+
+	// setSequencers activates primary, -1 for none, the sequencers without peers are unhealthy
+	setSequencers := func(primary int, ready []bool, peers []int) {
+		for i, ms := range sequencers {
+			ms.SetIsWithAdmin(true)
+
+			ms.SetIsActivated(i == primary)
+
+			ms.SetIsReady(ready[i])
+
+			ms.SetPeerCount(peers[i])
+
+			ms.SetUnsafeHash("unsafe-hash-1")
+
+			ms.SetUnsafeBlock(10, time.Now())
+		}
+	}
+
+	// Condition 1: 0 is primary but stopped and not ready
+	setSequencers(-1, []bool{false, true, true}, []int{5, 5, 5})
+
+	s.transition(PhaseMonitoring, 0, log)
+
+	// Situation 1: Should switch to 1 and start the cooldown
+	s.heartbeat(context.Background(), log)
+
+	if s.PrimarySequencerID() != 1 || s.Status(context.Background()).Damping.CooldownUntil == nil {
+		t.Log("should switch to 1 with cooldown", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Condition 2: 1 is primary and unhealthy, 2 is healthy, within the cooldown
+	setSequencers(1, []bool{true, true, true}, []int{0, 0, 5})
+
+	s.transition(PhaseMonitoring, 1, log)
+
+	// Situation 1: Should defer the switchover and keep monitoring 1
+	s.heartbeat(context.Background(), log)
+
+	if s.Phase() != PhaseMonitoring || s.PrimarySequencerID() != 1 || sequencers[2].GetIsActivated() {
+		t.Log("should defer the switchover", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 2: Should switch to 2 once the cooldown is over
+	s.lastSwitchover = time.Now().Add(-2 * time.Hour)
+
+	s.heartbeat(context.Background(), log)
+
+	if s.PrimarySequencerID() != 2 || !sequencers[2].GetIsActivated() {
+		t.Log("should switch to 2", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Condition 3: 2 is primary and unhealthy, the cooldown is over but the switchover budget is used up
+	setSequencers(2, []bool{true, true, true}, []int{5, 5, 0})
+
+	s.transition(PhaseMonitoring, 2, log)
+
+	s.lastSwitchover = time.Now().Add(-2 * time.Hour)
+
+	// Situation 1: Should defer the switchover
+	s.heartbeat(context.Background(), log)
+
+	if damping := s.Status(context.Background()).Damping; s.PrimarySequencerID() != 2 || damping.SwitchoversLastHour != 2 {
+		t.Log("should defer the switchover", s.PrimarySequencerID(), damping.SwitchoversLastHour)
+
+		t.Fail()
+	}
+
+	// Condition 4: 2 is primary but stopped and not ready, the switchover budget is used up
+	setSequencers(-1, []bool{true, true, false}, []int{5, 5, 5})
+
+	// Situation 1: Should keep 2 and escalate to degraded
+	s.heartbeat(context.Background(), log)
+
+	if s.Phase() != PhaseDegraded || s.PrimarySequencerID() != 2 || sequencers[0].GetIsActivated() {
+		t.Log("should escalate to degraded", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 2: Should switch to 0 once the switchovers are out of the budget window
+	for i := range s.switchovers {
+		s.switchovers[i] = s.switchovers[i].Add(-2 * time.Hour)
+	}
+
+	s.heartbeat(context.Background(), log)
+
+	if s.PrimarySequencerID() != 0 || !sequencers[0].GetIsActivated() {
+		t.Log("should switch to 0", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Condition 5: 0 is primary and healthy, within the cooldown
+	lastSwitchover := s.lastSwitchover
+
+	// Situation 1: Manual switchovers should neither count against the budget nor restart the cooldown
+	if id, err := s.Switchover(1); err != nil || id != 1 {
+		t.Log("should switch over to 1", id, err)
+
+		t.Fail()
+	}
+
+	if len(s.switchovers) != 1 || !s.lastSwitchover.Equal(lastSwitchover) {
+		t.Log("manual switchover is damped", len(s.switchovers), s.lastSwitchover)
+
+		t.Fail()
+	}
+}
+
+func TestStallDamping(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 2)
+
+	log := zap.NewNop()
+
+	// Default config, without health checks and fail-back
+	switchoverCooldown, err := time.ParseDuration(config.DefaultSwitchoverCooldown)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.switchoverCooldown = switchoverCooldown
+	s.maxSwitchovers = config.DefaultMaxSwitchovers
+
+	// setSequencers stops both sequencers, stopped is not ready and can not be restarted
+	setSequencers := func(stopped int) {
+		for i, ms := range sequencers {
+			ms.SetIsWithAdmin(true)
+
+			ms.SetIsActivated(false)
+
+			ms.SetIsReady(i != stopped)
+
+			ms.SetUnsafeHash("unsafe-hash-1")
+
+			ms.SetUnsafeBlock(10, time.Now())
+		}
+	}
+
+	// Condition 1: 0 is primary but stopped
+	setSequencers(0)
+
+	s.transition(PhaseMonitoring, 0, log)
+
+	// Situation 1: Should switch to 1 and start the cooldown
+	s.heartbeat(context.Background(), log)
+
+	if s.PrimarySequencerID() != 1 || !sequencers[1].GetIsActivated() || len(s.switchovers) != 1 {
+		t.Log("should switch to 1", s.PrimarySequencerID(), len(s.switchovers))
+
+		t.Fail()
+	}
+
+	// Condition 2: 1 stops right away, within the cooldown
+	setSequencers(1)
+
+	s.transition(PhaseMonitoring, 1, log)
+
+	// Situation 1: Should defer the switchover back to 0 and keep monitoring 1
+	s.heartbeat(context.Background(), log)
+
+	if s.Phase() != PhaseMonitoring || s.PrimarySequencerID() != 1 || sequencers[0].GetIsActivated() {
+		t.Log("should defer the switchover", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 2: Should switch to 0 once the cooldown is over
+	s.lastSwitchover = time.Now().Add(-switchoverCooldown)
+
+	s.heartbeat(context.Background(), log)
+
+	if s.PrimarySequencerID() != 0 || !sequencers[0].GetIsActivated() || len(s.switchovers) != 2 {
+		t.Log("should switch to 0", s.PrimarySequencerID(), len(s.switchovers))
+
+		t.Fail()
+	}
+
+	// Condition 3: The sequencers kept taking turns until the switchover budget is used up, 0 stops
+	for len(s.switchovers) < s.maxSwitchovers {
+		s.recordSwitchover(time.Now(), log)
+	}
+
+	s.lastSwitchover = time.Now().Add(-switchoverCooldown)
+
+	setSequencers(0)
+
+	s.transition(PhaseMonitoring, 0, log)
+
+	// Situation 1: Should keep 0 and escalate to degraded instead of flapping
+	s.heartbeat(context.Background(), log)
+
+	if s.Phase() != PhaseDegraded || s.PrimarySequencerID() != 0 || sequencers[1].GetIsActivated() {
+		t.Log("should escalate to degraded", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 2: Should stay degraded on the next retry while the budget is used up
+	s.heartbeat(context.Background(), log)
+
+	if s.Phase() != PhaseDegraded || s.PrimarySequencerID() != 0 || sequencers[1].GetIsActivated() {
+		t.Log("should stay degraded", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+}
+
+func TestQuarantine(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 2)
+
+	s.quarantineDuration = time.Hour
+
+	// Condition 1: all is ready, 0 fails to activate
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(i != 0)
+
+		ms.SetIsActivated(false)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+	}
+
+	// Situation 1: Should activate 1 and quarantine 0
+	if id := s.activateSequencerByID(context.Background(), 0, ""); id != 1 {
+		t.Log("should activate 1", id)
+
+		t.Fail()
+	}
+
+	if status := s.Status(context.Background()); status.Sequencers[0].QuarantinedUntil == nil || status.Sequencers[1].QuarantinedUntil != nil {
+		t.Log("only 0 should be quarantined")
+
+		t.Fail()
+	}
+
+	// Condition 2: 0 can be activated again but is still quarantined
+	for _, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(false)
+	}
+
+	// Situation 1: Should prefer 1 over the quarantined 0
+	if id := s.activateSequencerByID(context.Background(), 0, ""); id != 1 {
+		t.Log("should prefer 1", id)
+
+		t.Fail()
+	}
+
+	// Situation 2: Should activate 0 as last resort
+	sequencers[1].SetIsReady(false)
+
+	sequencers[1].SetIsActivated(false)
+
+	if id := s.activateSequencerByID(context.Background(), 0, ""); id != 0 {
+		t.Log("should activate 0 as last resort", id)
+
+		t.Fail()
+	}
+}
@@ -0,0 +1,100 @@
+package heartbeat
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/notify"
+	"go.uber.org/zap"
+)
+
+// degrade starts tracking the time without a primary sequencer, or with id kept as primary sequencer although
+// it stopped or stalls, as the switchover budget is spent, s.mu must be held
+func (s *Service) degrade(id int, log *zap.Logger) {
+	s.degradedSince = time.Now()
+	s.degradedRetries = 0
+
+	if id != -1 {
+		log.Error("Switchover budget is spent, keeping the primary sequencer until it allows switching over",
+			zap.Int("sequencer_id", id),
+			zap.Int("max_switchovers_per_hour", s.maxSwitchovers),
+		)
+
+		s.notify(notify.Event{
+			Event: notify.EventDegraded,
+			Text:  fmt.Sprintf("Switchover budget of %d per hour is spent, the primary sequencer stopped or stalls and is not switched over", s.maxSwitchovers),
+			Fields: map[string]string{
+				"sequencer": s.sequencerList[id],
+			},
+		}, log)
+
+		return
+	}
+
+	log.Error("No sequencer could be activated, retrying with backoff",
+		zap.Int("sequencers", len(s.sequencerList)),
+		zap.Duration("backoff", s.retryDelay(0)),
+	)
+
+	s.notify(notify.Event{
+		Event: notify.EventDegraded,
+		Text:  fmt.Sprintf("No sequencer could be activated out of %d, the chain has no primary sequencer", len(s.sequencerList)),
+		Fields: map[string]string{
+			"sequencers": strconv.Itoa(len(s.sequencerList)),
+		},
+	}, log)
+}
+
+// recoverPrimary ends the time without a primary sequencer as id is activated, s.mu must be held
+func (s *Service) recoverPrimary(id int, log *zap.Logger) {
+	duration := time.Since(s.degradedSince).Round(time.Second)
+
+	log.Info("Primary sequencer recovered",
+		zap.Int("primary_sequencer_id", id),
+		zap.Duration("duration", duration),
+		zap.Int("retries", s.degradedRetries),
+	)
+
+	fields := map[string]string{
+		"duration": duration.String(),
+		"retries":  strconv.Itoa(s.degradedRetries),
+	}
+
+	if id != -1 {
+		fields["sequencer"] = s.sequencerList[id]
+	}
+
+	s.notify(notify.Event{
+		Event:  notify.EventRecovered,
+		Text:   fmt.Sprintf("Primary sequencer recovered after %s without one", duration),
+		Fields: fields,
+	}, log)
+
+	s.degradedSince = time.Time{}
+	s.degradedRetries = 0
+}
+
+// retryDelay is the wait before the next promotion while bootstrapping or degraded,
+// which doubles with every failed retry up to the maximum backoff
+func (s *Service) retryDelay(retries int) time.Duration {
+	delay := s.degradedBackoff
+
+	for i := 0; i < retries && delay < s.degradedMaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, s.degradedMaxBackoff)
+}
+
+// nextHeartbeat is the wait before the next heartbeat, which retries promotion sooner while degraded
+func (s *Service) nextHeartbeat() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Phase() == PhaseDegraded {
+		return s.retryDelay(s.degradedRetries)
+	}
+
+	return s.checkInterval
+}
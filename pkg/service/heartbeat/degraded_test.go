@@ -0,0 +1,116 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/notify"
+	"go.uber.org/zap"
+)
+
+// testNotifier records the events sent to it.
+type testNotifier struct {
+	events chan notify.Event
+}
+
+func (n *testNotifier) Notify(_ context.Context, event notify.Event) error {
+	n.events <- event
+
+	return nil
+}
+
+func (n *testNotifier) String() string {
+	return "test"
+}
+
+func TestDegraded(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 2)
+
+	log := zap.NewNop()
+
+	notifier := &testNotifier{events: make(chan notify.Event, 4)}
+
+	s.degradedBackoff = time.Second
+	s.degradedMaxBackoff = 3 * time.Second
+	s.notifier = notifier
+
+	// Condition 1: No sequencer is ready
+	for _, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(false)
+
+		ms.SetIsReady(false)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+	}
+
+	s.transition(PhaseSwitching, -1, log)
+
+	s.heartbeat(context.Background(), log)
+
+	// Situation 1: Should be degraded and notify
+	if s.Phase() != PhaseDegraded || s.PrimarySequencerID() != -1 {
+		t.Log("should be degraded", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	if event := <-notifier.events; event.Event != notify.EventDegraded {
+		t.Log("should notify degraded", event.Event)
+
+		t.Fail()
+	}
+
+	status := s.Status(context.Background())
+
+	if status.DegradedSince == nil || status.Phase != "degraded" {
+		t.Log("status should be degraded", status.Phase)
+
+		t.Fail()
+	}
+
+	// Situation 2: Should retry with backoff up to the maximum
+	for i, expected := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		if delay := s.nextHeartbeat(); delay != expected {
+			t.Log("retry delay mismatch", i, delay)
+
+			t.Fail()
+		}
+
+		s.heartbeat(context.Background(), log)
+	}
+
+	if s.Phase() != PhaseDegraded || s.degradedRetries != 4 {
+		t.Log("should keep retrying", s.Phase(), s.degradedRetries)
+
+		t.Fail()
+	}
+
+	// Condition 2: 1 becomes ready
+	sequencers[1].SetIsReady(true)
+
+	s.heartbeat(context.Background(), log)
+
+	// Situation 1: Should promote 1 and notify
+	if s.Phase() != PhaseCoolingDown || s.PrimarySequencerID() != 1 || !sequencers[1].GetIsActivated() {
+		t.Log("should promote 1", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	if event := <-notifier.events; event.Event != notify.EventRecovered || event.Fields["sequencer"] != s.sequencerList[1] {
+		t.Log("should notify recovered", event)
+
+		t.Fail()
+	}
+
+	if delay := s.nextHeartbeat(); delay != time.Minute {
+		t.Log("should be back to the check interval", delay)
+
+		t.Fail()
+	}
+}
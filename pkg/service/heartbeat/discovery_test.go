@@ -0,0 +1,64 @@
+package heartbeat
+
+import (
+	"testing"
+)
+
+func TestUpdateSequencerList(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{
+		sequencerList: []string{"sequencer-0", "sequencer-1", "sequencer-2"},
+	}
+
+	s.setPrimary(1)
+
+	// Situation 1: Scale up, should keep 1
+	s.updateSequencerList([]string{"sequencer-0", "sequencer-1", "sequencer-2", "sequencer-3"})
+
+	if s.PrimarySequencerID() != 1 {
+		t.Log("tracked wrong sequencer", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 2: Sequencer 0 removed, should track sequencer-1 by its new ID
+	s.updateSequencerList([]string{"sequencer-1", "sequencer-2", "sequencer-3"})
+
+	if s.PrimarySequencerID() != 0 {
+		t.Log("tracked wrong sequencer", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 3: Primary sequencer removed, should track none
+	s.updateSequencerList([]string{"sequencer-2", "sequencer-3"})
+
+	if s.PrimarySequencerID() != -1 {
+		t.Log("tracked wrong sequencer", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 4: No primary sequencer, should track none
+	s.updateSequencerList([]string{"sequencer-0", "sequencer-1"})
+
+	if s.PrimarySequencerID() != -1 {
+		t.Log("tracked wrong sequencer", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 5: Scaled down while the primary is the highest ordinal and still terminating, should keep tracking it
+	s.updateSequencerList([]string{"sequencer-0", "sequencer-1", "sequencer-2"})
+
+	s.setPrimary(2)
+
+	s.updateSequencerList([]string{"sequencer-0", "sequencer-1", "sequencer-2"})
+
+	if s.PrimarySequencerID() != 2 {
+		t.Log("should keep the terminating primary", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+}
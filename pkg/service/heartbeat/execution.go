@@ -0,0 +1,217 @@
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+	"github.com/rss3-network/vsl-reconcile/pkg/discovery"
+	"github.com/rss3-network/vsl-reconcile/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// executionEndpoint is the op-geth endpoint paired with a sequencer, empty if it is unknown.
+// The configured template takes precedence over discovered sidecars, s.mu must be held
+func (s *Service) executionEndpoint(sequencer string) string {
+	if s.executionURL == "" {
+		return s.executionEndpoints[sequencer]
+	}
+
+	executionEndpoint, err := discovery.ExecutionEndpoint(s.executionURL, sequencer)
+	if err != nil {
+		return ""
+	}
+
+	return executionEndpoint
+}
+
+// refreshExecutionEndpoints discovers the op-geth sidecars of the sequencers, s.mu must be held
+func (s *Service) refreshExecutionEndpoints(ctx context.Context, log *zap.Logger) {
+	executionDiscoverer, ok := s.discoverer.(discovery.ExecutionDiscoverer)
+	if !ok || s.executionURL != "" {
+		return
+	}
+
+	executionEndpoints, err := executionDiscoverer.ExecutionEndpoints(ctx)
+	if err != nil {
+		log.Error("Failed to get execution endpoints", zap.Error(err))
+
+		return
+	}
+
+	s.executionEndpoints = executionEndpoints
+}
+
+// checkExecutionReady checks that the op-geth paired with sequencer is reachable and not syncing,
+// so a sequencer is never activated on top of a broken execution client, s.mu must be held
+func (s *Service) checkExecutionReady(ctx context.Context, sequencer string) error {
+	executionEndpoint := s.executionEndpoint(sequencer)
+	if executionEndpoint == "" {
+		return nil
+	}
+
+	syncing, err := s.client.GetSyncing(ctx, executionEndpoint)
+	if err != nil {
+		return fmt.Errorf("op-geth of sequencer %s is unreachable: %w", sequencer, err)
+	}
+
+	if syncing {
+		return fmt.Errorf("op-geth of sequencer %s is syncing", sequencer)
+	}
+
+	return nil
+}
+
+// checkExecution checks that the op-geth paired with sequencer is ready, is less than the critical execution lag behind
+// unsafeNumber, the unsafe head of op-node, and has more than the critical execution peers.
+// It applies whether or not the execution health checks are enabled, s.mu must be held
+func (s *Service) checkExecution(ctx context.Context, sequencer string, unsafeNumber int64) error {
+	if err := s.checkExecutionReady(ctx, sequencer); err != nil {
+		return err
+	}
+
+	executionEndpoint := s.executionEndpoint(sequencer)
+	if executionEndpoint == "" {
+		return nil
+	}
+
+	blockNumber, err := s.client.GetBlockNumber(ctx, executionEndpoint)
+	if err != nil {
+		return fmt.Errorf("op-geth of sequencer %s is unreachable: %w", sequencer, err)
+	}
+
+	metrics.ExecutionHeight.WithLabelValues(sequencer).Set(float64(blockNumber))
+
+	if lag := max(unsafeNumber-blockNumber, 0); float64(lag) >= s.health.ExecutionLag.Crit {
+		return fmt.Errorf("op-geth of sequencer %s is %d blocks behind the unsafe head", sequencer, lag)
+	}
+
+	// Not every op-geth serves net_peerCount, which is then not checked
+	if peers, err := s.client.GetNetPeerCount(ctx, executionEndpoint); err == nil && float64(peers) <= s.health.ExecutionPeers.Crit {
+		return fmt.Errorf("op-geth of sequencer %s has %d peers", sequencer, peers)
+	}
+
+	return nil
+}
+
+// checkPrimaryExecution checks the op-geth paired with the primary sequencer at unsafeNumber, and reports whether it stays primary.
+// It is only switched over if the op-geth of another ready sequencer passes the check, s.mu must be held
+func (s *Service) checkPrimaryExecution(ctx context.Context, unsafeNumber int64, log *zap.Logger) bool {
+	primaryErr := s.checkExecution(ctx, s.sequencerList[s.primarySequencerID], unsafeNumber)
+	if primaryErr == nil {
+		return true
+	}
+
+	others := slices.DeleteFunc(s.candidates(s.primarySequencerID), func(id int) bool { return id == s.primarySequencerID })
+
+	for _, id := range others {
+		if s.checkCandidateExecution(ctx, id) == nil {
+			log.Warn("Execution client of primary sequencer is unhealthy, switching...", zap.Error(primaryErr))
+
+			return false
+		}
+	}
+
+	log.Warn("Execution client of primary sequencer is unhealthy, but no other sequencer has a healthy one", zap.Error(primaryErr))
+
+	return true
+}
+
+// checkCandidateExecution checks the op-geth paired with the candidate id against the unsafe head of its op-node, s.mu must be held
+func (s *Service) checkCandidateExecution(ctx context.Context, id int) error {
+	sequencer := s.sequencerList[id]
+
+	if s.executionEndpoint(sequencer) == "" {
+		return nil
+	}
+
+	_, unsafeNumber, _, err := s.client.GetOPSyncStatus(ctx, sequencer)
+	if err != nil {
+		return err
+	}
+
+	return s.checkExecution(ctx, sequencer, unsafeNumber)
+}
+
+// rankByExecution moves the candidates whose op-geth fails the check last, so a primary sequencer switched over
+// for its op-geth is not restarted, candidates keep their order otherwise, s.mu must be held
+func (s *Service) rankByExecution(ctx context.Context, candidates []int) []int {
+	var ready, failed []int
+
+	for _, id := range candidates {
+		if s.checkCandidateExecution(ctx, id) != nil {
+			failed = append(failed, id)
+
+			continue
+		}
+
+		ready = append(ready, id)
+	}
+
+	return append(ready, failed...)
+}
+
+// executionCheck scores whether op-geth is reachable, not syncing, and how far it is behind the unsafe head of op-node.
+type executionCheck struct {
+	threshold config.Threshold
+}
+
+func (c *executionCheck) Name() string {
+	return config.HealthCheckExecution
+}
+
+func (c *executionCheck) Check(ctx context.Context, probe *Probe) CheckResult {
+	if probe.ExecutionEndpoint == "" {
+		return CheckResult{Skipped: true, Reason: "no op-geth endpoint"}
+	}
+
+	syncing, err := probe.Client.GetSyncing(ctx, probe.ExecutionEndpoint)
+	if err != nil {
+		return CheckResult{Reason: fmt.Sprintf("op-geth is unreachable: %s", err)}
+	}
+
+	if syncing {
+		return CheckResult{Reason: "op-geth is syncing"}
+	}
+
+	blockNumber, err := probe.Client.GetBlockNumber(ctx, probe.ExecutionEndpoint)
+	if err != nil {
+		return CheckResult{Reason: fmt.Sprintf("op-geth is unreachable: %s", err)}
+	}
+
+	metrics.ExecutionHeight.WithLabelValues(probe.Sequencer).Set(float64(blockNumber))
+
+	lag := max(probe.SyncStatus.UnsafeL2.Number-blockNumber, 0)
+
+	return CheckResult{
+		Score:  score(float64(lag), c.threshold, false),
+		Reason: fmt.Sprintf("op-geth is %d blocks behind the unsafe head", lag),
+	}
+}
+
+// executionPeersCheck scores how many peers op-geth is connected to.
+type executionPeersCheck struct {
+	threshold config.Threshold
+}
+
+func (c *executionPeersCheck) Name() string {
+	return config.HealthCheckExecutionPeers
+}
+
+func (c *executionPeersCheck) Check(ctx context.Context, probe *Probe) CheckResult {
+	if probe.ExecutionEndpoint == "" {
+		return CheckResult{Skipped: true, Reason: "no op-geth endpoint"}
+	}
+
+	// An unreachable op-geth is scored by the execution check
+	peers, err := probe.Client.GetNetPeerCount(ctx, probe.ExecutionEndpoint)
+	if err != nil {
+		return CheckResult{Skipped: true, Reason: err.Error()}
+	}
+
+	return CheckResult{
+		Score:  score(float64(peers), c.threshold, true),
+		Reason: fmt.Sprintf("%d op-geth peers connected", peers),
+	}
+}
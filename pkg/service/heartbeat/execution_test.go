@@ -0,0 +1,119 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+	"go.uber.org/zap"
+)
+
+func TestExecution(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 2)
+
+	log := zap.NewNop()
+
+	health := config.Health{
+		Checks:         []string{config.HealthCheckExecution},
+		Policy:         config.HealthPolicyMin,
+		DegradedScore:  0.75,
+		UnhealthyScore: 0.25,
+		ExecutionLag:   config.Threshold{Warn: 10, Crit: 60},
+		ExecutionPeers: config.Threshold{Warn: 1, Crit: 0},
+	}
+
+	s.health = health
+	s.healthChecks = newHealthChecks(health)
+
+	// Each mock sequencer serves its op-geth as well
+	endpoints := s.sequencerList
+
+	s.executionEndpoints = map[string]string{
+		endpoints[0]: endpoints[0],
+		endpoints[1]: endpoints[1],
+	}
+
+	// Condition 1: The op-geth of 0 is syncing
+	for _, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-100")
+
+		ms.SetUnsafeBlock(100, time.Now())
+
+		ms.SetExecutionBlock(100)
+
+		ms.SetExecutionPeerCount(5)
+	}
+
+	sequencers[0].SetIsSyncing(true)
+
+	// Situation 1: 0 is not ready to be activated, so 1 is promoted
+	if primarySequencerID := s.activateSequencerByID(context.Background(), 0, ""); primarySequencerID != 1 || sequencers[0].GetIsActivated() {
+		t.Log("should promote 1", primarySequencerID)
+
+		t.Fail()
+	}
+
+	s.transition(PhaseMonitoring, 1, log)
+
+	if status := s.Status(context.Background()); status.Sequencers[1].ExecutionEndpoint != endpoints[1] ||
+		status.Sequencers[0].Health == nil || status.Sequencers[0].Health.Health != HealthUnhealthy {
+		t.Log("status execution mismatch", status.Sequencers[1].ExecutionEndpoint, status.Sequencers[0].Health)
+
+		t.Fail()
+	}
+
+	// Condition 2: The op-geth of 0 is synced, and the op-geth of 1 is stuck 80 blocks behind
+	sequencers[0].SetIsSyncing(false)
+
+	sequencers[1].SetExecutionBlock(20)
+
+	s.heartbeat(context.Background(), log)
+
+	// Situation 1: Unhealthy 1 should be switched over to 0
+	if s.PrimarySequencerID() != 0 || !sequencers[0].GetIsActivated() || sequencers[1].GetIsActivated() {
+		t.Log("should switch over to 0", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Condition 3: No health check is enabled, 0 is primary, the op-geth of 0 lost its peers
+	s.healthChecks = nil
+
+	sequencers[1].SetExecutionBlock(100)
+
+	sequencers[0].SetExecutionPeerCount(0)
+
+	s.transition(PhaseMonitoring, 0, log)
+
+	// Situation 1: Should switch over to 1 regardless
+	s.heartbeat(context.Background(), log)
+
+	if s.PrimarySequencerID() != 1 || !sequencers[1].GetIsActivated() || sequencers[0].GetIsActivated() {
+		t.Log("should switch over to 1", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Condition 4: 1 is primary, the op-geth of both is syncing
+	for _, ms := range sequencers {
+		ms.SetIsSyncing(true)
+	}
+
+	s.transition(PhaseMonitoring, 1, log)
+
+	// Situation 1: No other sequencer has a healthy op-geth, should keep 1
+	s.heartbeat(context.Background(), log)
+
+	if s.Phase() != PhaseMonitoring || s.PrimarySequencerID() != 1 || !sequencers[1].GetIsActivated() {
+		t.Log("should keep 1", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+}
@@ -0,0 +1,393 @@
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+	"github.com/rss3-network/vsl-reconcile/internal/rpc"
+	"github.com/rss3-network/vsl-reconcile/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// Health is the aggregate health of a sequencer.
+type Health int
+
+const (
+	// HealthHealthy sequencers score at least the degraded score
+	HealthHealthy Health = iota
+	// HealthDegraded sequencers score below the degraded score, which is only reported
+	HealthDegraded
+	// HealthUnhealthy sequencers score below the unhealthy score, which hands block production over
+	HealthUnhealthy
+)
+
+func (h Health) String() string {
+	switch h {
+	case HealthHealthy:
+		return "healthy"
+	case HealthDegraded:
+		return "degraded"
+	case HealthUnhealthy:
+		return "unhealthy"
+	default:
+		return "unknown"
+	}
+}
+
+func (h Health) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// CheckResult is the outcome of a single health check.
+type CheckResult struct {
+	Name string `json:"name"`
+	// Score is from 0 (unhealthy) to 1 (healthy)
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+	// Skipped checks had no data to score, and are left out of the aggregate score
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// HealthReport is the aggregate health of a sequencer and the checks it is made of.
+type HealthReport struct {
+	Health Health        `json:"health"`
+	Score  float64       `json:"score"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Reasons returns the reasons of the checks which scored below 1.
+func (r *HealthReport) Reasons() []string {
+	var reasons []string
+
+	for _, check := range r.Checks {
+		if !check.Skipped && check.Score < 1 {
+			reasons = append(reasons, check.Name+": "+check.Reason)
+		}
+	}
+
+	return reasons
+}
+
+// Probe is what the health checks of a sequencer are run against.
+type Probe struct {
+	Client     rpc.API
+	Sequencer  string
+	SyncStatus *rpc.SyncStatus
+	// Latency is how long a single optimism_syncStatus attempt took, 0 if the attempt failed
+	Latency time.Duration
+	// ExecutionEndpoint is the paired op-geth, empty if it is unknown
+	ExecutionEndpoint string
+}
+
+// HealthCheck scores a single signal of sequencer health.
+type HealthCheck interface {
+	Name() string
+	Check(ctx context.Context, probe *Probe) CheckResult
+}
+
+// newHealthChecks returns the health checks enabled by cfg.
+func newHealthChecks(cfg config.Health) []HealthCheck {
+	checks := make([]HealthCheck, 0, len(cfg.Checks))
+
+	for _, name := range cfg.Checks {
+		switch name {
+		case config.HealthCheckL1Head:
+			checks = append(checks, &l1HeadCheck{threshold: cfg.L1HeadAge})
+		case config.HealthCheckSafeLag:
+			checks = append(checks, &safeLagCheck{threshold: cfg.SafeLag})
+		case config.HealthCheckFinalizedLag:
+			checks = append(checks, &finalizedLagCheck{threshold: cfg.FinalizedLag})
+		case config.HealthCheckPeers:
+			checks = append(checks, &peersCheck{threshold: cfg.Peers})
+		case config.HealthCheckRPCLatency:
+			checks = append(checks, &rpcLatencyCheck{threshold: cfg.RPCLatency})
+		case config.HealthCheckExecution:
+			checks = append(checks, &executionCheck{threshold: cfg.ExecutionLag})
+		case config.HealthCheckExecutionPeers:
+			checks = append(checks, &executionPeersCheck{threshold: cfg.ExecutionPeers})
+		}
+	}
+
+	return checks
+}
+
+// score scores value 1 up to the warn threshold and 0 from the crit threshold, linearly in between.
+// Values below the thresholds are worse if lowerIsWorse, like connected peers.
+func score(value float64, threshold config.Threshold, lowerIsWorse bool) float64 {
+	if lowerIsWorse {
+		value, threshold.Warn, threshold.Crit = -value, -threshold.Warn, -threshold.Crit
+	}
+
+	switch {
+	case value >= threshold.Crit:
+		return 0
+	case value <= threshold.Warn:
+		return 1
+	default:
+		return (threshold.Crit - value) / (threshold.Crit - threshold.Warn)
+	}
+}
+
+// checkHealth probes sequencer and runs the health checks against it, s.mu must be held
+func (s *Service) checkHealth(ctx context.Context, sequencer string) (*HealthReport, error) {
+	started := time.Now()
+
+	// The latency is timed on a single attempt, as retries would add their backoff to it
+	syncStatus, err := s.client.GetSyncStatusOnce(ctx, sequencer)
+	latency := time.Since(started)
+
+	if err != nil {
+		latency = 0
+
+		syncStatus, err = s.client.GetSyncStatus(ctx, sequencer)
+	}
+
+	if err != nil {
+		s.recordError(sequencer, err)
+
+		return nil, err
+	}
+
+	return s.evaluateHealth(ctx, &Probe{
+		Client:     s.client,
+		Sequencer:  sequencer,
+		SyncStatus: syncStatus,
+		Latency:    latency,
+
+		ExecutionEndpoint: s.executionEndpoint(sequencer),
+	}), nil
+}
+
+// evaluateHealth runs the health checks against probe, and aggregates their scores by the health policy
+func (s *Service) evaluateHealth(ctx context.Context, probe *Probe) *HealthReport {
+	report := &HealthReport{
+		Health: HealthHealthy,
+		Score:  1,
+		Checks: make([]CheckResult, 0, len(s.healthChecks)),
+	}
+
+	var scores []float64
+
+	for _, check := range s.healthChecks {
+		result := check.Check(ctx, probe)
+		result.Name = check.Name()
+
+		report.Checks = append(report.Checks, result)
+
+		if result.Skipped {
+			continue
+		}
+
+		scores = append(scores, result.Score)
+
+		metrics.HealthCheckScore.WithLabelValues(probe.Sequencer, result.Name).Set(result.Score)
+	}
+
+	if len(scores) > 0 {
+		switch s.health.Policy {
+		case config.HealthPolicyMean:
+			sum := 0.0
+
+			for _, score := range scores {
+				sum += score
+			}
+
+			report.Score = sum / float64(len(scores))
+		default:
+			report.Score = slices.Min(scores)
+		}
+	}
+
+	switch {
+	case report.Score < s.health.UnhealthyScore:
+		report.Health = HealthUnhealthy
+	case report.Score < s.health.DegradedScore:
+		report.Health = HealthDegraded
+	}
+
+	metrics.HealthScore.WithLabelValues(probe.Sequencer).Set(report.Score)
+
+	return report
+}
+
+// rankByHealth polls the candidates concurrently, and orders healthy ones first and unhealthy ones last,
+// candidates of equal health keep their order
+func (s *Service) rankByHealth(ctx context.Context, candidates []int, log *zap.Logger) []int {
+	if len(s.healthChecks) == 0 {
+		return candidates
+	}
+
+	health := s.candidateHealth(ctx, candidates, log)
+
+	ranked := slices.Clone(candidates)
+
+	slices.SortStableFunc(ranked, func(a, b int) int {
+		return int(health[a]) - int(health[b])
+	})
+
+	return ranked
+}
+
+// candidateHealth polls the candidates concurrently, candidates which could not be polled are unhealthy
+func (s *Service) candidateHealth(ctx context.Context, candidates []int, log *zap.Logger) map[int]Health {
+	health := make(map[int]Health, len(candidates))
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	for _, id := range candidates {
+		wg.Add(1)
+
+		go func(id int, sequencer string) {
+			defer wg.Done()
+
+			candidateHealth := HealthUnhealthy
+
+			report, err := s.checkHealth(ctx, sequencer)
+			if err != nil {
+				log.Error("Failed to check candidate health", zap.String("sequencer", sequencer), zap.Error(err))
+			} else {
+				candidateHealth = report.Health
+
+				if report.Health != HealthHealthy {
+					log.Info("Candidate is not healthy",
+						zap.String("sequencer", sequencer),
+						zap.Stringer("health", report.Health),
+						zap.Float64("score", report.Score),
+						zap.Strings("reasons", report.Reasons()),
+					)
+				}
+			}
+
+			mu.Lock()
+			health[id] = candidateHealth
+			mu.Unlock()
+		}(id, s.sequencerList[id])
+	}
+
+	wg.Wait()
+
+	return health
+}
+
+// healthierCandidate reports whether any other sequencer is healthier than the primary sequencer, s.mu must be held
+func (s *Service) healthierCandidate(ctx context.Context, primaryHealth Health, log *zap.Logger) bool {
+	others := slices.DeleteFunc(s.candidates(s.primarySequencerID), func(id int) bool { return id == s.primarySequencerID })
+
+	for _, health := range s.candidateHealth(ctx, others, log) {
+		if health < primaryHealth {
+			return true
+		}
+	}
+
+	return false
+}
+
+// l1HeadCheck scores how old the L1 head of the sequencer is.
+type l1HeadCheck struct {
+	threshold config.Threshold
+}
+
+func (c *l1HeadCheck) Name() string {
+	return config.HealthCheckL1Head
+}
+
+func (c *l1HeadCheck) Check(_ context.Context, probe *Probe) CheckResult {
+	if probe.SyncStatus.HeadL1.Timestamp == 0 {
+		return CheckResult{Skipped: true, Reason: "no l1 head"}
+	}
+
+	age := probe.SyncStatus.L1HeadLag()
+
+	return CheckResult{
+		Score:  score(age.Seconds(), c.threshold, false),
+		Reason: fmt.Sprintf("l1 head is %s old", age.Truncate(time.Second)),
+	}
+}
+
+// safeLagCheck scores how far the safe head of the sequencer is behind its unsafe head.
+type safeLagCheck struct {
+	threshold config.Threshold
+}
+
+func (c *safeLagCheck) Name() string {
+	return config.HealthCheckSafeLag
+}
+
+func (c *safeLagCheck) Check(_ context.Context, probe *Probe) CheckResult {
+	return lagCheck(probe.SyncStatus.UnsafeL2, probe.SyncStatus.SafeL2, "safe", c.threshold)
+}
+
+// finalizedLagCheck scores how far the finalized head of the sequencer is behind its unsafe head.
+type finalizedLagCheck struct {
+	threshold config.Threshold
+}
+
+func (c *finalizedLagCheck) Name() string {
+	return config.HealthCheckFinalizedLag
+}
+
+func (c *finalizedLagCheck) Check(_ context.Context, probe *Probe) CheckResult {
+	return lagCheck(probe.SyncStatus.UnsafeL2, probe.SyncStatus.FinalizedL2, "finalized", c.threshold)
+}
+
+// lagCheck scores how many blocks head is behind the unsafe head, it is skipped until head is known
+func lagCheck(unsafe, head rpc.BlockRef, name string, threshold config.Threshold) CheckResult {
+	if head.Hash == "" {
+		return CheckResult{Skipped: true, Reason: fmt.Sprintf("no %s head", name)}
+	}
+
+	lag := max(unsafe.Number-head.Number, 0)
+
+	return CheckResult{
+		Score:  score(float64(lag), threshold, false),
+		Reason: fmt.Sprintf("%s head is %d blocks behind the unsafe head", name, lag),
+	}
+}
+
+// peersCheck scores how many peers the sequencer is connected to, it is skipped if opp2p_peers is unavailable.
+type peersCheck struct {
+	threshold config.Threshold
+}
+
+func (c *peersCheck) Name() string {
+	return config.HealthCheckPeers
+}
+
+func (c *peersCheck) Check(ctx context.Context, probe *Probe) CheckResult {
+	peers, err := probe.Client.GetPeerCount(ctx, probe.Sequencer)
+	if err != nil {
+		return CheckResult{Skipped: true, Reason: err.Error()}
+	}
+
+	return CheckResult{
+		Score:  score(float64(peers), c.threshold, true),
+		Reason: fmt.Sprintf("%d peers connected", peers),
+	}
+}
+
+// rpcLatencyCheck scores how long the sequencer took to report its sync status.
+type rpcLatencyCheck struct {
+	threshold config.Threshold
+}
+
+func (c *rpcLatencyCheck) Name() string {
+	return config.HealthCheckRPCLatency
+}
+
+func (c *rpcLatencyCheck) Check(_ context.Context, probe *Probe) CheckResult {
+	if probe.Latency == 0 {
+		return CheckResult{Skipped: true, Reason: "sync status needed a retry"}
+	}
+
+	return CheckResult{
+		Score:  score(probe.Latency.Seconds(), c.threshold, false),
+		Reason: fmt.Sprintf("sync status took %s", probe.Latency.Round(time.Millisecond)),
+	}
+}
@@ -0,0 +1,181 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+	"go.uber.org/zap"
+)
+
+// testCheck returns a fixed result.
+type testCheck struct {
+	result CheckResult
+}
+
+func (c *testCheck) Name() string {
+	return "test"
+}
+
+func (c *testCheck) Check(_ context.Context, _ *Probe) CheckResult {
+	return c.result
+}
+
+func TestHealthScore(t *testing.T) {
+	t.Parallel()
+
+	threshold := config.Threshold{Warn: 10, Crit: 20}
+
+	peers := config.Threshold{Warn: 3, Crit: 0}
+
+	for _, c := range []struct {
+		value        float64
+		threshold    config.Threshold
+		lowerIsWorse bool
+		expected     float64
+	}{
+		{5, threshold, false, 1},
+		{10, threshold, false, 1},
+		{15, threshold, false, 0.5},
+		{20, threshold, false, 0},
+		{25, threshold, false, 0},
+		{5, peers, true, 1},
+		{3, peers, true, 1},
+		{1.5, peers, true, 0.5},
+		{0, peers, true, 0},
+	} {
+		if actual := score(c.value, c.threshold, c.lowerIsWorse); actual != c.expected {
+			t.Log("score mismatch", c.value, c.threshold, actual, c.expected)
+
+			t.Fail()
+		}
+	}
+}
+
+func TestEvaluateHealth(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{
+		health: config.Health{
+			Policy:         config.HealthPolicyMin,
+			DegradedScore:  0.75,
+			UnhealthyScore: 0.25,
+		},
+		healthChecks: []HealthCheck{
+			&testCheck{CheckResult{Score: 1}},
+			&testCheck{CheckResult{Score: 0.5, Reason: "half"}},
+			&testCheck{CheckResult{Score: 0, Skipped: true}},
+		},
+	}
+
+	probe := &Probe{Sequencer: "test"}
+
+	// Situation 1: The lowest score is degraded, skipped checks are left out
+	if report := s.evaluateHealth(context.Background(), probe); report.Health != HealthDegraded || report.Score != 0.5 || len(report.Reasons()) != 1 {
+		t.Log("should be degraded by the lowest score", report)
+
+		t.Fail()
+	}
+
+	// Situation 2: The mean score is healthy
+	s.health.Policy = config.HealthPolicyMean
+
+	if report := s.evaluateHealth(context.Background(), probe); report.Health != HealthHealthy || report.Score != 0.75 {
+		t.Log("should be healthy by the mean score", report)
+
+		t.Fail()
+	}
+
+	// Situation 3: A failing check degrades the mean score, and makes the lowest score unhealthy
+	s.healthChecks = append(s.healthChecks, &testCheck{CheckResult{Score: 0}})
+
+	if report := s.evaluateHealth(context.Background(), probe); report.Health != HealthDegraded || report.Score != 0.5 {
+		t.Log("should be degraded by the mean score", report)
+
+		t.Fail()
+	}
+
+	s.health.Policy = config.HealthPolicyMin
+
+	if report := s.evaluateHealth(context.Background(), probe); report.Health != HealthUnhealthy || report.Score != 0 {
+		t.Log("should be unhealthy by the lowest score", report)
+
+		t.Fail()
+	}
+}
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 2)
+
+	log := zap.NewNop()
+
+	health := config.Health{
+		Checks:         []string{config.HealthCheckSafeLag, config.HealthCheckPeers},
+		Policy:         config.HealthPolicyMin,
+		DegradedScore:  0.75,
+		UnhealthyScore: 0.25,
+		SafeLag:        config.Threshold{Warn: 10, Crit: 20},
+		Peers:          config.Threshold{Warn: 3, Crit: 0},
+	}
+
+	s.health = health
+	s.healthChecks = newHealthChecks(health)
+
+	// Condition 1: 0 is primary with its safe head 30 blocks behind, 1 has a single peer
+	for _, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-100")
+
+		ms.SetUnsafeBlock(100, time.Now())
+	}
+
+	sequencers[0].SetIsActivated(true)
+
+	sequencers[0].SetSafeBlock(70)
+
+	sequencers[0].SetPeerCount(5)
+
+	sequencers[1].SetSafeBlock(95)
+
+	sequencers[1].SetPeerCount(1)
+
+	s.transition(PhaseMonitoring, 0, log)
+
+	status := s.Status(context.Background())
+
+	if status.Sequencers[0].Health == nil || status.Sequencers[0].Health.Health != HealthUnhealthy ||
+		status.Sequencers[1].Health == nil || status.Sequencers[1].Health.Health != HealthDegraded {
+		t.Log("status health mismatch", status.Sequencers[0].Health, status.Sequencers[1].Health)
+
+		t.Fail()
+	}
+
+	s.heartbeat(context.Background(), log)
+
+	// Situation 1: Unhealthy 0 should be switched over to degraded 1
+	if s.PrimarySequencerID() != 1 || sequencers[0].GetIsActivated() || !sequencers[1].GetIsActivated() {
+		t.Log("should switch over to 1", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Condition 2: 1 becomes unhealthy as well
+	sequencers[1].SetSafeBlock(50)
+
+	s.transition(PhaseMonitoring, 1, log)
+
+	s.heartbeat(context.Background(), log)
+
+	// Situation 1: No other sequencer is healthier, so 1 stays primary
+	if s.PrimarySequencerID() != 1 || s.Phase() != PhaseMonitoring || !sequencers[1].GetIsActivated() {
+		t.Log("should stay on 1", s.PrimarySequencerID(), s.Phase())
+
+		t.Fail()
+	}
+}
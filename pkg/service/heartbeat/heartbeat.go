@@ -3,22 +3,125 @@ package heartbeat
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rss3-network/vsl-reconcile/config"
 	"github.com/rss3-network/vsl-reconcile/internal/rpc"
 	"github.com/rss3-network/vsl-reconcile/internal/safe"
+	"github.com/rss3-network/vsl-reconcile/pkg/discovery"
 	"github.com/rss3-network/vsl-reconcile/pkg/kube"
+	"github.com/rss3-network/vsl-reconcile/pkg/metrics"
+	"github.com/rss3-network/vsl-reconcile/pkg/notify"
 	"github.com/rss3-network/vsl-reconcile/pkg/service"
+	"github.com/rss3-network/vsl-reconcile/pkg/state"
 	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
 )
 
 var _ service.Service = (*Service)(nil)
 
 type Service struct {
-	sequencerList []string
+	discoverer    discovery.Discoverer
+	client        rpc.API
 	checkInterval time.Duration
 	maxBlockTime  time.Duration
+
+	// priorities are the configured sequencer priorities, annotatedPriorities are read from pod annotations,
+	// failback hands block production back to a preferred sequencer once it is ready
+	priorities          map[string]int
+	annotatedPriorities map[string]int
+	failback            bool
+
+	// handoffSyncTimeout is how long a candidate is waited for to sync the handed-off unsafe block
+	handoffSyncTimeout time.Duration
+
+	// health configures how healthChecks are aggregated, unhealthy primary sequencers are switched over
+	health       config.Health
+	healthChecks []HealthCheck
+
+	// executionURL is the template of the op-geth endpoints, which are discovered as executionEndpoints otherwise
+	executionURL       string
+	executionEndpoints map[string]string
+
+	// bootstrapTimeout is how long bootstrap is retried before the heartbeat loop starts degraded, 0 to retry forever
+	bootstrapTimeout time.Duration
+
+	// mu guards the sequencers and the tracked primary,
+	// which are shared by the heartbeat loop, discovery and manual switchovers
+	mu                 sync.Mutex
+	sequencerList      []string
+	primarySequencerID int
+	currentBlockHeight int64
+	currentBlockTime   time.Time
+	lastSwitchover     time.Time
+
+	// failedChecks counts the consecutive heartbeats which could not reach the primary sequencer
+	failedChecks int
+
+	// phase is the Phase of the heartbeat loop, which is only changed with s.mu held but read without it,
+	// so the readiness probe never waits for a heartbeat in progress
+	phase atomic.Int32
+
+	// savedPrimarySequencerID, savedPhase and savedMaintenance are the last saved state, which is only saved again once any changes
+	stateSaved              bool
+	savedPrimarySequencerID int
+	savedPhase              Phase
+	savedMaintenance        bool
+
+	// switchovers are the switchovers within the budget window, automatic switchovers are deferred
+	// for switchoverCooldown after the last one and once maxSwitchovers are used up.
+	// quarantined sequencers failed to activate, and are only tried as last resort until the quarantine ends
+	switchovers        []time.Time
+	switchoverCooldown time.Duration
+	maxSwitchovers     int
+	quarantined        map[string]time.Time
+	quarantineDuration time.Duration
+
+	// degradedSince is when the heartbeat loop was left without a primary sequencer,
+	// promotion is retried with backoff from degradedBackoff up to degradedMaxBackoff
+	degradedSince      time.Time
+	degradedRetries    int
+	degradedBackoff    time.Duration
+	degradedMaxBackoff time.Duration
+
+	// safeHeadNumber is the highest safe head, which last advanced at safeHeadTime. It is stalled once it has not advanced
+	// for safeHeadStallTimeout, which holds automatic switchovers and promotions if safeHeadHold
+	safeHeadNumber       int64
+	safeHeadTime         time.Time
+	safeHeadStalled      bool
+	safeHeadStallTimeout time.Duration
+	safeHeadHold         bool
+
+	// snapshot is published for the status API whenever the heartbeat loop moves on
+	snapshot atomic.Pointer[clusterSnapshot]
+
+	// notifier sends notable events to operators, nil if notifications are disabled
+	notifier notify.Notifier
+
+	// incidents are the latest split-brain incidents
+	incidents []Incident
+
+	// store persists the tracked primary across restarts, nil if it is not persisted
+	store state.Store
+
+	errorsMu   sync.Mutex
+	lastErrors map[string]sequencerError
+
+	kubeClient           *kubernetes.Clientset
+	stsName              string
+	stsNamespace         string
+	maintenance          atomic.Bool
+	maintenanceAnnotated atomic.Bool
+}
+
+// New creates the heartbeat service, which acts on the sequencers through client.
+func New(client rpc.API) *Service {
+	return &Service{
+		client: client,
+	}
 }
 
 func (s *Service) Run(pool *safe.Pool) error {
@@ -28,38 +131,159 @@ func (s *Service) Run(pool *safe.Pool) error {
 		log.Debug("sequencer found", zap.Int("id", id), zap.String("sequencer", sequencer))
 	}
 
+	if watcher, ok := s.discoverer.(discovery.Watcher); ok {
+		pool.GoCtx(func(ctx context.Context) {
+			if err := watcher.Watch(ctx, s.updateSequencerList); err != nil {
+				log.Error("failed to watch sequencers", zap.Error(err), zap.String("discovery", watcher.String()))
+			}
+		})
+	}
+
+	pool.GoCtx(func(ctx context.Context) {
+		s.bootstrap(ctx, log)
+		s.Loop(ctx)
+	})
+
+	return nil
+}
+
+// bootstrap finds or promotes the primary sequencer before the heartbeat loop starts,
+// retrying with backoff until it succeeds or the bootstrap timeout elapses
+func (s *Service) bootstrap(ctx context.Context, log *zap.Logger) {
+	s.refreshMaintenanceAnnotation(ctx, log)
+
+	saved := s.loadState(log)
+
+	// Maintenance mode paused through the admin API on the previous leader stays on
+	if saved != nil && saved.Maintenance {
+		log.Info("Resuming saved maintenance mode")
+		s.setMaintenance(true)
+	}
+
+	started := time.Now()
+
+	for retries := 0; ; retries++ {
+		// Discovery must not change sequencers during a bootstrap attempt
+		s.mu.Lock()
+
+		primarySequencerID := s.bootstrapPrimary(ctx, saved, log)
+
+		timedOut := s.bootstrapTimeout > 0 && time.Since(started) >= s.bootstrapTimeout
+
+		if primarySequencerID != -1 || timedOut || ctx.Err() != nil {
+			// Start heartbeat loop
+			log.Info("start heartbeat loop", zap.Int("primary_sequencer_id", primarySequencerID), zap.Int("bootstrap_retries", retries))
+
+			if primarySequencerID == -1 {
+				log.Error("Bootstrap timed out without a primary sequencer", zap.Duration("bootstrap_timeout", s.bootstrapTimeout))
+				s.transition(PhaseDegraded, -1, log)
+			} else {
+				s.transition(PhaseMonitoring, primarySequencerID, log)
+			}
+
+			s.restoreState(saved)
+			s.publish()
+			s.mu.Unlock()
+
+			return
+		}
+
+		delay := s.retryDelay(retries)
+		s.mu.Unlock()
+
+		log.Warn("No primary sequencer found or promoted, retrying bootstrap...", zap.Int("retries", retries), zap.Duration("backoff", delay))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// bootstrapPrimary makes one attempt to find or promote the primary sequencer, -1 if there is none, s.mu must be held
+func (s *Service) bootstrapPrimary(ctx context.Context, saved *state.State, log *zap.Logger) int {
+	s.refreshPriorities(ctx, log)
+	s.refreshExecutionEndpoints(ctx, log)
+
+	if primarySequencerID := s.resumePrimary(ctx, saved, log); primarySequencerID != -1 {
+		if !s.Maintenance() {
+			s.deactivateExtraSequencers(ctx, primarySequencerID, log)
+		}
+
+		return primarySequencerID
+	}
+
+	if s.Maintenance() {
+		// Bootstrap could deactivate or promote sequencers, only look up the active one
+		log.Warn("Maintenance mode, skipping bootstrap")
+
+		return s.firstActiveSequencer(ctx, log)
+	}
+
 	// Bootstrap
 	log.Debug("start bootstrap")
 
-	primarySequencerID, err := Bootstrap(s.sequencerList)
+	primarySequencerID, err := s.Bootstrap(ctx)
 	if err != nil {
 		log.Error("failed to bootstrap", zap.Error(err))
 	}
 
-	// Start heartbeat loop
-	log.Info("start heartbeat loop", zap.Int("primary_sequencer_id", primarySequencerID))
-
-	pool.GoCtx(func(_ context.Context) {
-		s.Loop(primarySequencerID)
-	})
-
-	return nil
+	return primarySequencerID
 }
 
 func (s *Service) Init(cfg *config.Config) error {
-	clientset, err := kube.Client()
+	discoverer, err := discovery.New(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to initialize kubernetes client: %w", err)
+		return err
 	}
 
-	sequencerList, err := DiscoverStsEndpoints(clientset, cfg.DiscoverySTS, cfg.DiscoveryNS)
+	sequencerList, err := discoverer.Discover(context.Background())
 	if err != nil {
-		return fmt.Errorf("failed to discover sequencers: %w", err)
+		return fmt.Errorf("failed to discover sequencers from %s: %w", discoverer, err)
 	}
 
+	s.discoverer = discoverer
 	s.sequencerList = sequencerList
 	s.checkInterval = cfg.CheckInterval
 	s.maxBlockTime = cfg.MaxBlockTime
+	s.degradedBackoff = cfg.DegradedBackoff
+	s.degradedMaxBackoff = cfg.DegradedMaxBackoff
+	s.notifier = notify.New(cfg)
+	s.priorities = cfg.SequencerPriorities
+	s.failback = cfg.Failback
+	s.handoffSyncTimeout = cfg.HandoffSyncTimeout
+	s.switchoverCooldown = cfg.SwitchoverCooldown
+	s.maxSwitchovers = cfg.MaxSwitchovers
+	s.quarantineDuration = cfg.Quarantine
+	s.bootstrapTimeout = cfg.BootstrapTimeout
+	s.health = cfg.Health
+	s.healthChecks = newHealthChecks(cfg.Health)
+	s.executionURL = cfg.ExecutionURL
+	s.safeHeadStallTimeout = cfg.SafeHeadStallTimeout
+	s.safeHeadHold = cfg.SafeHeadHoldPromotion
+	s.primarySequencerID = -1 // Not bootstrapped yet
+	s.phase.Store(int32(PhaseBootstrapping))
+	metrics.Phase.WithLabelValues(PhaseBootstrapping.String()).Set(1)
+	s.publish()
+
+	s.setMaintenance(cfg.Maintenance)
+
+	s.store, err = state.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize state store: %w", err)
+	}
+
+	// The maintenance annotation is only available on the sequencer StatefulSet
+	if cfg.DiscoverySTS != "" {
+		s.kubeClient, err = kube.Client()
+		if err != nil {
+			return fmt.Errorf("failed to initialize kubernetes client: %w", err)
+		}
+
+		s.stsName = cfg.DiscoverySTS
+		s.stsNamespace = cfg.DiscoveryNS
+	}
 
 	return nil
 }
@@ -69,31 +293,38 @@ func (s *Service) String() string {
 }
 
 // activateSequencerByID: Try to activate one of all sequencers from a specified ID.
-// All sequencers are equal, but some sequencers are "more equal" than others.
-func activateSequencerByID(id int, unsafeHash string, sequencersList []string) int {
+// All sequencers are equal, but some sequencers are "more equal" than others, so they are tried by priority.
+func (s *Service) activateSequencerByID(ctx context.Context, id int, unsafeHash string) int {
+	return s.activateSequencers(ctx, s.candidates(id), unsafeHash)
+}
+
+// activateSequencers tries to activate the healthiest candidates closest to the unsafe head first, those whose op-geth
+// fails the check and quarantined ones last, and returns the ID of the activated one, -1 if none
+func (s *Service) activateSequencers(ctx context.Context, candidates []int, unsafeHash string) int {
 	log := zap.L().With(zap.String("service", "heartbeat"))
 
-	for i := 0; i < len(sequencersList); i++ {
-		// index is the absolute position of sequencer in the list
-		index := (i + id) % len(sequencersList)
+	ranked := s.rankByExecution(ctx, s.rankByHealth(ctx, s.rankCandidates(ctx, candidates, unsafeHash, log), log))
 
+	for _, index := range s.deprioritizeQuarantined(ranked) {
 		// Activates sequencer and handles possible failures internally
-		if activated, err := activateSequencer(sequencersList[index], unsafeHash); activated {
+		if activated, err := s.activateSequencer(ctx, s.sequencerList[index], unsafeHash); activated {
 			return index // Return the ID of the activated sequencer
 		} else if err != nil {
 			log.Error("Failed to activate sequencer",
-				zap.String("sequencer", sequencersList[index]),
+				zap.String("sequencer", s.sequencerList[index]),
 				zap.Error(err),
 			)
+
+			s.quarantine(s.sequencerList[index], err, log)
 		}
 	}
 	// No sequencer could be activated
 	return -1
 }
 
-// activateSequencer: Activate a sequencer and return whether it was successful
-func activateSequencer(sequencer string, unsafeHash string) (bool, error) {
-	unsafeHashResponse, _, isReady, err := rpc.GetOPSyncStatus(sequencer)
+// activateSequencer: Activate a sequencer and return whether it was successful, s.mu must be held
+func (s *Service) activateSequencer(ctx context.Context, sequencer string, unsafeHash string) (bool, error) {
+	unsafeHashResponse, _, isReady, err := s.client.GetOPSyncStatus(ctx, sequencer)
 	if err != nil {
 		return false, err
 	}
@@ -102,26 +333,34 @@ func activateSequencer(sequencer string, unsafeHash string) (bool, error) {
 		return false, fmt.Errorf("sequencer %s is not ready", sequencer)
 	}
 
-	// Use unsafeHash from the response if initial unsafeHash is empty
+	if err = s.checkExecutionReady(ctx, sequencer); err != nil {
+		return false, err
+	}
+
+	// Use unsafeHash from the response if initial unsafeHash is empty,
+	// otherwise the sequencer must have synced the handed-off unsafe block, so the unsafe chain is never forked
 	if unsafeHash == "" {
 		unsafeHash = unsafeHashResponse
+	} else if err = s.waitForUnsafeBlock(ctx, sequencer, unsafeHash); err != nil {
+		return false, err
 	}
 
-	err = rpc.ActivateSequencer(sequencer, unsafeHash)
+	err = s.client.ActivateSequencer(ctx, sequencer, unsafeHash)
 	if err != nil {
 		// Ensure this sequencer is deactivated even it failed to activate
-		_, _ = rpc.DeactivateSequencer(sequencer)
+		_, _ = s.client.DeactivateSequencer(ctx, sequencer)
 		return false, err
 	}
 
 	return true, nil
 }
 
-func Bootstrap(sequencersList []string) (int, error) {
+// Bootstrap finds the active primary sequencer, or promotes a new one if there is none, s.mu must be held
+func (s *Service) Bootstrap(ctx context.Context) (int, error) {
 	log := zap.L().With(zap.String("service", "heartbeat"))
 
 	log.Debug("Determining current primary sequencer")
-	primarySequencerID := findActivePrimary(sequencersList, log)
+	primarySequencerID := s.findActivePrimary(ctx, log)
 
 	// Attempt to promote a new primary if no active primary was found
 	if primarySequencerID == -1 {
@@ -129,21 +368,31 @@ func Bootstrap(sequencersList []string) (int, error) {
 
 		var err error
 
-		primarySequencerID, err = promoteNewPrimary(sequencersList)
+		primarySequencerID, err = s.promoteNewPrimary(ctx)
 		if err != nil {
 			return -1, err // Promotion failed, propagate error
 		}
 	}
 
-	log.Info("Primary sequencer is active.", zap.Int("id", primarySequencerID), zap.String("sequencer", sequencersList[primarySequencerID]))
+	log.Info("Primary sequencer is active.", zap.Int("id", primarySequencerID), zap.String("sequencer", s.sequencerList[primarySequencerID]))
 
 	return primarySequencerID, nil
 }
 
 // findActivePrimary finds the active primary sequencer which is processing blocks
-func findActivePrimary(sequencersList []string, log *zap.Logger) int {
-	for id, sequencer := range sequencersList {
-		isActive, err := rpc.CheckSequencerActive(sequencer)
+func (s *Service) findActivePrimary(ctx context.Context, log *zap.Logger) int {
+	id := s.firstActiveSequencer(ctx, log)
+	if id != -1 {
+		s.deactivateExtraSequencers(ctx, id, log)
+	}
+
+	return id
+}
+
+// firstActiveSequencer finds the first active sequencer without changing any sequencer
+func (s *Service) firstActiveSequencer(ctx context.Context, log *zap.Logger) int {
+	for id, sequencer := range s.sequencerList {
+		isActive, err := s.client.CheckSequencerActive(ctx, sequencer)
 		if err != nil {
 			log.Error("Failed to get sequencer status", zap.Int("id", id), zap.String("sequencer", sequencer), zap.Error(err))
 			continue
@@ -151,7 +400,6 @@ func findActivePrimary(sequencersList []string, log *zap.Logger) int {
 
 		if isActive {
 			log.Info("Found active primary sequencer", zap.Int("id", id), zap.String("sequencer", sequencer))
-			deactivateExtraSequencers(id, sequencersList, log)
 
 			return id
 		}
@@ -161,18 +409,18 @@ func findActivePrimary(sequencersList []string, log *zap.Logger) int {
 }
 
 // deactivateExtraSequencers deactivates all sequencers except the active primary
-func deactivateExtraSequencers(primaryID int, sequencersList []string, log *zap.Logger) {
-	for id, sequencer := range sequencersList {
+func (s *Service) deactivateExtraSequencers(ctx context.Context, primaryID int, log *zap.Logger) {
+	for id, sequencer := range s.sequencerList {
 		if id != primaryID {
-			if _, err := rpc.DeactivateSequencer(sequencer); err != nil {
+			if _, err := s.client.DeactivateSequencer(ctx, sequencer); err != nil {
 				log.Error("Failed to deactivate sequencer", zap.Int("id", id), zap.String("sequencer", sequencer), zap.Error(err))
 			}
 		}
 	}
 }
 
-func promoteNewPrimary(sequencersList []string) (int, error) {
-	primarySequencerID := activateSequencerByID(0, "", sequencersList)
+func (s *Service) promoteNewPrimary(ctx context.Context) (int, error) {
+	primarySequencerID := s.activateSequencerByID(ctx, 0, "")
 	if primarySequencerID == -1 {
 		return -1, fmt.Errorf("failed to activate any sequencers")
 	}
@@ -181,92 +429,170 @@ func promoteNewPrimary(sequencersList []string) (int, error) {
 }
 
 // Loop is the main heartbeat loop, which monitors the status of the primary sequencer
-func (s *Service) Loop(primarySequencerID int) {
+func (s *Service) Loop(ctx context.Context) {
 	log := zap.L().With(zap.String("service", "heartbeat"))
 
-	currentBlockTime := time.Now()
-	currentBlockHeight := int64(0)
-
 	// begin the heartbeat loop
 	for {
-		time.Sleep(s.checkInterval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.nextHeartbeat()):
+		}
 
-		isActive, err := s.checkPrimarySequencerStatus(primarySequencerID)
-		if err != nil {
-			log.Error("Failed to check primary sequencer status", zap.Error(err))
+		s.mu.Lock()
+		s.heartbeat(ctx, log)
+		s.mu.Unlock()
+	}
+}
 
-			continue
-		}
+// updateSequencerList replaces the discovered sequencers,
+// the primary sequencer is tracked by its endpoint as IDs can change.
+func (s *Service) updateSequencerList(sequencerList []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-		if !isActive {
-			log.Info("Primary sequencer is not active, switching...")
-			primarySequencerID = s.switchSequencer(primarySequencerID, "", log)
+	log := zap.L().With(zap.String("service", "heartbeat"))
 
-			continue
-		}
+	primarySequencer := ""
+	if s.primarySequencerID != -1 {
+		primarySequencer = s.sequencerList[s.primarySequencerID]
+	}
 
-		blockHeight, err := s.checkBlockHeight(primarySequencerID, log, currentBlockHeight, currentBlockTime)
-		if err != nil || blockHeight == currentBlockHeight {
-			// blockHeight is correct, do nothing
-			continue
-		}
+	s.sequencerList = sequencerList
 
-		currentBlockHeight = blockHeight
-		currentBlockTime = time.Now()
+	primarySequencerID := slices.Index(sequencerList, primarySequencer)
+
+	switch {
+	case primarySequencer == "":
+		log.Info("Sequencers changed", zap.Strings("sequencers", sequencerList))
+	case primarySequencerID == -1:
+		// The heartbeat loop promotes a new primary sequencer
+		log.Warn("Primary sequencer is removed", zap.String("sequencer", primarySequencer), zap.Strings("sequencers", sequencerList))
+		s.transition(PhaseSwitching, -1, log)
+	default:
+		log.Info("Sequencers changed", zap.Strings("sequencers", sequencerList), zap.Int("primary_sequencer_id", primarySequencerID))
+
+		// Same primary sequencer, so keep the block time tracking
+		s.primarySequencerID = primarySequencerID
+		metrics.PrimarySequencerID.Set(float64(primarySequencerID))
 	}
+
+	s.publish()
 }
 
-func (s *Service) checkPrimarySequencerStatus(primarySequencerID int) (bool, error) {
-	isActive, err := rpc.CheckSequencerActive(s.sequencerList[primarySequencerID])
+// setPrimary tracks id as the primary sequencer and restarts the block time tracking, s.mu must be held
+func (s *Service) setPrimary(id int) {
+	s.primarySequencerID = id
+	s.currentBlockHeight = 0
+	s.currentBlockTime = time.Now()
+	s.failedChecks = 0
+
+	metrics.PrimarySequencerID.Set(float64(id))
+
+	s.saveState()
+	s.publish()
+}
+
+func (s *Service) checkPrimarySequencerStatus(ctx context.Context, primarySequencerID int) (bool, error) {
+	isActive, err := s.client.CheckSequencerActive(ctx, s.sequencerList[primarySequencerID])
 
 	if err != nil {
+		s.recordError(s.sequencerList[primarySequencerID], err)
+
 		return false, err
 	}
 
 	return isActive, nil
 }
 
-// checkBlockHeight checks the current block height of the primary sequencer
-func (s *Service) checkBlockHeight(primarySequencerID int, log *zap.Logger, currentBlockHeight int64, currentBlockTime time.Time) (int64, error) {
+// checkBlockHeight checks the current block height and block time of the primary sequencer.
+// The block time is the timestamp of the unsafe L2 head, so stalls are measured by actual block production,
+// but never before currentBlockTime, which gives a new primary sequencer time to produce its first block.
+func (s *Service) checkBlockHeight(ctx context.Context, primarySequencerID int, log *zap.Logger, currentBlockHeight int64, currentBlockTime time.Time) (int64, time.Time, error) {
 	log.Debug("Start checking current block height")
 
-	_, blockHeight, _, err := rpc.GetOPSyncStatus(s.sequencerList[primarySequencerID])
+	sequencer := s.sequencerList[primarySequencerID]
+
+	syncStatus, err := s.client.GetSyncStatus(ctx, sequencer)
 
 	if err != nil {
 		log.Error("Failed to get block status from primary sequencer", zap.Error(err), zap.Int("sequencer_id", primarySequencerID))
+		s.recordError(sequencer, err)
 
-		return currentBlockHeight, err
+		return currentBlockHeight, currentBlockTime, err
+	}
+
+	blockHeight := syncStatus.UnsafeL2.Number
+
+	blockTime := syncStatus.UnsafeL2.Time()
+	if blockTime.Before(currentBlockTime) {
+		blockTime = currentBlockTime
 	}
 
 	if blockHeight > currentBlockHeight {
-		log.Info("New block height found", zap.Int64("new_block_height", blockHeight))
+		log.Info("New block height found", zap.Int64("new_block_height", blockHeight), zap.Time("block_time", blockTime))
 
-		return blockHeight, nil
+		return blockHeight, blockTime, nil
 	}
 
-	if time.Since(currentBlockTime) > s.maxBlockTime {
-		log.Warn("Block time exceeds maximum tolerance, attempting to restart sequencer...")
-		s.switchSequencer(primarySequencerID, "", log)
+	return currentBlockHeight, blockTime, nil
+}
+
+// observeSequencers polls every sequencer and reports their heads, so backup sequencers are observed as well as
+// the primary one. It returns the sync statuses by sequencer ID, nil for those which could not be polled, s.mu must be held
+func (s *Service) observeSequencers(ctx context.Context, log *zap.Logger) []*rpc.SyncStatus {
+	ids := make([]int, len(s.sequencerList))
+	for id := range ids {
+		ids[id] = id
 	}
 
-	return currentBlockHeight, nil
+	statuses := s.syncStatuses(ctx, ids, log)
+
+	for id, syncStatus := range statuses {
+		if syncStatus == nil {
+			continue
+		}
+
+		sequencer := s.sequencerList[id]
+
+		metrics.UnsafeL2Height.WithLabelValues(sequencer).Set(float64(syncStatus.UnsafeL2.Number))
+		metrics.L1HeadLag.WithLabelValues(sequencer).Set(syncStatus.L1HeadLag().Seconds())
+
+		if syncStatus.SafeL2.Hash != "" {
+			metrics.SafeLag.WithLabelValues(sequencer).Set(float64(syncStatus.UnsafeL2.Number - syncStatus.SafeL2.Number))
+		}
+	}
+
+	return statuses
 }
 
-func (s *Service) switchSequencer(currentSequencerID int, unsafeHash string, log *zap.Logger) int {
+// switchSequencer deactivates the primary sequencer and activates the next available one, -1 if none could be activated
+func (s *Service) switchSequencer(ctx context.Context, currentSequencerID int, log *zap.Logger) int {
 	log.Info("Handling failure of the primary sequencer", zap.Int("sequencer_id", currentSequencerID))
 
-	_, err := rpc.DeactivateSequencer(s.sequencerList[currentSequencerID])
+	metrics.SwitchoverAttempts.Inc()
+
+	// The next sequencer continues from the unsafe block the primary stopped at, unknown if it could not be stopped
+	unsafeHash, err := s.client.DeactivateSequencer(ctx, s.sequencerList[currentSequencerID])
 
 	if err != nil {
 		log.Error("Failed to deactivate sequencer", zap.Error(err))
+
+		unsafeHash = ""
 	}
 
-	newPrimaryID := activateSequencerByID(currentSequencerID, unsafeHash, s.sequencerList)
+	newPrimaryID := s.activateSequencerByID(ctx, currentSequencerID, unsafeHash)
 
 	if newPrimaryID == -1 {
-		log.Fatal("Failed to activate any sequencer")
+		metrics.SwitchoverFailures.Inc()
+		log.Error("Failed to activate any sequencer")
+
+		return -1
 	}
 
+	metrics.SwitchoverSuccesses.Inc()
+
 	log.Info("New primary sequencer activated.", zap.Int("new_primary_id", newPrimaryID))
 
 	return newPrimaryID
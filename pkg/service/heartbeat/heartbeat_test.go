@@ -1,8 +1,10 @@
 package heartbeat
 
 import (
+	"context"
 	"testing"
 
+	"github.com/rss3-network/vsl-reconcile/internal/rpc"
 	"github.com/rss3-network/vsl-reconcile/test"
 )
 
@@ -42,6 +44,11 @@ func Test_activateSequencerByID(t *testing.T) {
 }
 
 func activateSequencerByIDCondition1(t *testing.T, sequencers []*test.MockSequencer, endpoints []string) {
+	s := &Service{
+		client:        &rpc.Client{},
+		sequencerList: endpoints,
+	}
+
 	// Condition 1: all sequencers stopped, all is ready
 	startWithID := 0
 
@@ -56,7 +63,7 @@ func activateSequencerByIDCondition1(t *testing.T, sequencers []*test.MockSequen
 
 	startWithID = 0
 
-	activatedSequencerID := activateSequencerByID(startWithID, "unsafe-hash-1.1", endpoints)
+	activatedSequencerID := s.activateSequencerByID(context.Background(), startWithID, "unsafe-hash-1")
 
 	if activatedSequencerID != startWithID {
 		t.Log("activated wrong sequencer", activatedSequencerID)
@@ -83,7 +90,7 @@ func activateSequencerByIDCondition1(t *testing.T, sequencers []*test.MockSequen
 
 	startWithID = 1
 
-	activatedSequencerID = activateSequencerByID(startWithID, "unsafe-hash-1.2", endpoints)
+	activatedSequencerID = s.activateSequencerByID(context.Background(), startWithID, "unsafe-hash-1")
 
 	if activatedSequencerID != startWithID {
 		t.Log("activated wrong sequencer", activatedSequencerID)
@@ -101,6 +108,11 @@ func activateSequencerByIDCondition1(t *testing.T, sequencers []*test.MockSequen
 }
 
 func activateSequencerByIDCondition2(t *testing.T, sequencers []*test.MockSequencer, endpoints []string) {
+	s := &Service{
+		client:        &rpc.Client{},
+		sequencerList: endpoints,
+	}
+
 	// Condition 2: all sequencers stopped, some is not ready
 	notReadyIndex := 0
 
@@ -115,7 +127,7 @@ func activateSequencerByIDCondition2(t *testing.T, sequencers []*test.MockSequen
 		ms.SetUnsafeHash("unsafe-hash-2")
 	}
 
-	activatedSequencerID := activateSequencerByID(0, "unsafe-hash-2.1", endpoints)
+	activatedSequencerID := s.activateSequencerByID(context.Background(), 0, "unsafe-hash-2")
 
 	if activatedSequencerID != 1 {
 		t.Log("activated wrong sequencer", activatedSequencerID)
@@ -140,7 +152,7 @@ func activateSequencerByIDCondition2(t *testing.T, sequencers []*test.MockSequen
 		ms.SetIsReady(i != notReadyIndex)
 	}
 
-	activatedSequencerID = activateSequencerByID(2, "unsafe-hash-2.2", endpoints)
+	activatedSequencerID = s.activateSequencerByID(context.Background(), 2, "unsafe-hash-2")
 
 	if activatedSequencerID != 2 {
 		t.Log("activated wrong sequencer", activatedSequencerID)
@@ -167,7 +179,7 @@ func activateSequencerByIDCondition2(t *testing.T, sequencers []*test.MockSequen
 		ms.SetIsReady(i != notReadyIndex)
 	}
 
-	activatedSequencerID = activateSequencerByID(2, "unsafe-hash-2.3", endpoints)
+	activatedSequencerID = s.activateSequencerByID(context.Background(), 2, "unsafe-hash-2")
 
 	if activatedSequencerID != 0 {
 		t.Log("activated wrong sequencer", activatedSequencerID)
@@ -185,6 +197,11 @@ func activateSequencerByIDCondition2(t *testing.T, sequencers []*test.MockSequen
 }
 
 func activateSequencerByIDCondition3(t *testing.T, sequencers []*test.MockSequencer, endpoints []string) {
+	s := &Service{
+		client:        &rpc.Client{},
+		sequencerList: endpoints,
+	}
+
 	// Condition 1: all sequencers stopped, none is ready
 	// Situation 1: Start with 0, should no active
 	for _, ms := range sequencers {
@@ -197,7 +214,7 @@ func activateSequencerByIDCondition3(t *testing.T, sequencers []*test.MockSequen
 		ms.SetUnsafeHash("unsafe-hash-3")
 	}
 
-	activatedSequencerID := activateSequencerByID(0, "unsafe-hash-3.1", endpoints)
+	activatedSequencerID := s.activateSequencerByID(context.Background(), 0, "unsafe-hash-3")
 
 	if activatedSequencerID != -1 {
 		t.Log("activated wrong sequencer", activatedSequencerID)
@@ -223,7 +240,7 @@ func activateSequencerByIDCondition3(t *testing.T, sequencers []*test.MockSequen
 	}
 
 	// Situation 2: Start with 1, should no active
-	activatedSequencerID = activateSequencerByID(1, "unsafe-hash-3.2", endpoints)
+	activatedSequencerID = s.activateSequencerByID(context.Background(), 1, "unsafe-hash-3")
 
 	if activatedSequencerID != -1 {
 		t.Log("activated wrong sequencer", activatedSequencerID)
@@ -280,6 +297,11 @@ func TestBootstrap(t *testing.T) {
 }
 
 func BootstrapCondition1(t *testing.T, sequencers []*test.MockSequencer, endpoints []string) {
+	s := &Service{
+		client:        &rpc.Client{},
+		sequencerList: endpoints,
+	}
+
 	// Condition 1: all sequencers stopped, all is ready
 	// Situation 1: Should activate 0
 	for _, ms := range sequencers {
@@ -292,7 +314,7 @@ func BootstrapCondition1(t *testing.T, sequencers []*test.MockSequencer, endpoin
 		ms.SetUnsafeHash("unsafe-hash-1.1")
 	}
 
-	activatedSequencerID, err := Bootstrap(endpoints)
+	activatedSequencerID, err := s.Bootstrap(context.Background())
 
 	if err != nil {
 		t.Log("should no error", err)
@@ -314,6 +336,11 @@ func BootstrapCondition1(t *testing.T, sequencers []*test.MockSequencer, endpoin
 }
 
 func BootstrapCondition2(t *testing.T, sequencers []*test.MockSequencer, endpoints []string) {
+	s := &Service{
+		client:        &rpc.Client{},
+		sequencerList: endpoints,
+	}
+
 	// Condition 2: all sequencers stopped, some is not ready
 	notReadyIndex := 0
 
@@ -329,7 +356,7 @@ func BootstrapCondition2(t *testing.T, sequencers []*test.MockSequencer, endpoin
 
 	// Situation 1: Should activate 1
 
-	activatedSequencerID, err := Bootstrap(endpoints)
+	activatedSequencerID, err := s.Bootstrap(context.Background())
 
 	if err != nil {
 		t.Log("should no error", err)
@@ -351,6 +378,11 @@ func BootstrapCondition2(t *testing.T, sequencers []*test.MockSequencer, endpoin
 }
 
 func BootstrapCondition3(t *testing.T, sequencers []*test.MockSequencer, endpoints []string) {
+	s := &Service{
+		client:        &rpc.Client{},
+		sequencerList: endpoints,
+	}
+
 	// Condition 3: one sequencer started, all is ready
 	activeIndex := 1
 
@@ -365,7 +397,7 @@ func BootstrapCondition3(t *testing.T, sequencers []*test.MockSequencer, endpoin
 		ms.SetUnsafeHash("unsafe-hash-3.1")
 	}
 
-	activatedSequencerID, err := Bootstrap(endpoints)
+	activatedSequencerID, err := s.Bootstrap(context.Background())
 
 	if err != nil {
 		t.Log("should no error", err)
@@ -389,6 +421,11 @@ func BootstrapCondition3(t *testing.T, sequencers []*test.MockSequencer, endpoin
 }
 
 func BootstrapCondition4(t *testing.T, sequencers []*test.MockSequencer, endpoints []string) {
+	s := &Service{
+		client:        &rpc.Client{},
+		sequencerList: endpoints,
+	}
+
 	// Condition 4: multiple sequencer started, all is ready
 	// Situation 1: Should activate 0 and deactivate others
 	for _, ms := range sequencers {
@@ -401,7 +438,7 @@ func BootstrapCondition4(t *testing.T, sequencers []*test.MockSequencer, endpoin
 		ms.SetUnsafeHash("unsafe-hash-4.1")
 	}
 
-	activatedSequencerID, err := Bootstrap(endpoints)
+	activatedSequencerID, err := s.Bootstrap(context.Background())
 
 	if err != nil {
 		t.Log("should no error", err)
@@ -425,6 +462,11 @@ func BootstrapCondition4(t *testing.T, sequencers []*test.MockSequencer, endpoin
 }
 
 func BootstrapCondition5(t *testing.T, sequencers []*test.MockSequencer, endpoints []string) {
+	s := &Service{
+		client:        &rpc.Client{},
+		sequencerList: endpoints,
+	}
+
 	// Condition 5: no sequencer ready
 	// Situation 1: Ready w/ empty unsafe hash (invalid state)
 	for _, ms := range sequencers {
@@ -437,7 +479,7 @@ func BootstrapCondition5(t *testing.T, sequencers []*test.MockSequencer, endpoin
 		ms.SetUnsafeHash("") // Empty is invalid
 	}
 
-	activatedSequencerID, err := Bootstrap(endpoints)
+	activatedSequencerID, err := s.Bootstrap(context.Background())
 
 	if err == nil {
 		t.Log("should be error")
@@ -470,7 +512,7 @@ func BootstrapCondition5(t *testing.T, sequencers []*test.MockSequencer, endpoin
 		ms.SetUnsafeHash("unsafe-hash-5.2")
 	}
 
-	activatedSequencerID, err = Bootstrap(endpoints)
+	activatedSequencerID, err = s.Bootstrap(context.Background())
 
 	if err == nil {
 		t.Log("should be error")
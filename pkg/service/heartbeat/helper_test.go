@@ -0,0 +1,41 @@
+package heartbeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/internal/rpc"
+	"github.com/rss3-network/vsl-reconcile/test"
+)
+
+// newTestService starts count mock sequencers, and returns a monitoring Service tracking them which checks every minute
+// and tolerates a minute without blocks. The mock sequencers are closed once the test finishes
+func newTestService(t *testing.T, count int) (*Service, []*test.MockSequencer) {
+	t.Helper()
+
+	sequencers := make([]*test.MockSequencer, count)
+
+	endpoints := make([]string, count)
+
+	for i := range sequencers {
+		var err error
+
+		sequencers[i], endpoints[i], err = test.NewMockSequencer()
+		if err != nil {
+			t.Fatal("failed to prepare mock sequencer", i, err)
+		}
+
+		t.Cleanup(sequencers[i].Close)
+	}
+
+	s := &Service{
+		client:        &rpc.Client{},
+		sequencerList: endpoints,
+		checkInterval: time.Minute,
+		maxBlockTime:  time.Minute,
+	}
+
+	s.phase.Store(int32(PhaseMonitoring))
+
+	return s, sequencers
+}
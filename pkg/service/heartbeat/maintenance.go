@@ -0,0 +1,67 @@
+package heartbeat
+
+import (
+	"context"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/kube"
+	"github.com/rss3-network/vsl-reconcile/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// annotationMaintenance pauses automatic failover when set to "true" on the sequencer StatefulSet
+const annotationMaintenance = "vsl.rss3.io/maintenance"
+
+// Maintenance reports whether automatic failover is paused.
+// In maintenance mode the heartbeat keeps monitoring the sequencers, but never activates or deactivates them.
+func (s *Service) Maintenance() bool {
+	return s.maintenance.Load() || s.maintenanceAnnotated.Load()
+}
+
+// SetMaintenance pauses or resumes automatic failover, which is saved with the state so it survives a restart or a leader change.
+func (s *Service) SetMaintenance(enabled bool) {
+	s.setMaintenance(enabled)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.saveState()
+}
+
+// setMaintenance pauses or resumes automatic failover without saving it
+func (s *Service) setMaintenance(enabled bool) {
+	if s.maintenance.Swap(enabled) != enabled {
+		zap.L().Info("Maintenance mode changed", zap.String("service", "heartbeat"), zap.Bool("maintenance", enabled))
+	}
+
+	s.updateMaintenanceMetric()
+}
+
+// refreshMaintenanceAnnotation reads the maintenance annotation of the sequencer StatefulSet
+func (s *Service) refreshMaintenanceAnnotation(ctx context.Context, log *zap.Logger) {
+	if s.kubeClient == nil {
+		return
+	}
+
+	value, err := kube.StatefulSetAnnotation(ctx, s.kubeClient, s.stsNamespace, s.stsName, annotationMaintenance)
+	if err != nil {
+		log.Error("Failed to get maintenance annotation", zap.Error(err))
+
+		return
+	}
+
+	annotated := value == "true"
+
+	if s.maintenanceAnnotated.Swap(annotated) != annotated {
+		log.Info("Maintenance annotation changed", zap.Bool("maintenance", annotated))
+	}
+
+	s.updateMaintenanceMetric()
+}
+
+func (s *Service) updateMaintenanceMetric() {
+	if s.Maintenance() {
+		metrics.Maintenance.Set(1)
+	} else {
+		metrics.Maintenance.Set(0)
+	}
+}
@@ -0,0 +1,129 @@
+package heartbeat
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/state"
+	"go.uber.org/zap"
+)
+
+func TestMaintenance(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 3)
+
+	s.SetMaintenance(true)
+
+	if !s.Maintenance() {
+		t.Fatal("should be in maintenance mode")
+	}
+
+	// Condition 1: 0 is primary but stopped, all is ready
+	// Situation 1: Should not switch
+	for _, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(false)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+	}
+
+	s.setPrimary(0)
+
+	s.heartbeat(context.Background(), zap.NewNop())
+
+	if s.PrimarySequencerID() != 0 {
+		t.Log("primary sequencer should not change", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	for i, ms := range sequencers {
+		if ms.GetIsActivated() {
+			t.Log("sequencer state is incorrect", i)
+
+			t.Fail()
+		}
+	}
+
+	// Condition 2: no primary tracked, 1 and 2 are started
+	// Situation 1: Should track 1 and keep 2 started
+	for i, ms := range sequencers {
+		ms.SetIsActivated(i != 0)
+	}
+
+	s.setPrimary(-1)
+
+	s.heartbeat(context.Background(), zap.NewNop())
+
+	if s.PrimarySequencerID() != 1 {
+		t.Log("tracked wrong sequencer", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	for i, ms := range sequencers {
+		if ms.GetIsActivated() != (i != 0) {
+			t.Log("sequencer state is incorrect", i)
+
+			t.Fail()
+		}
+	}
+}
+
+func TestMaintenanceLeaderHandoff(t *testing.T) {
+	t.Parallel()
+
+	leader, sequencers := newTestService(t, 2)
+
+	log := zap.NewNop()
+
+	// The replicas share the state store, like the configmap store
+	store := state.NewFile(filepath.Join(t.TempDir(), "state.json"))
+
+	leader.primarySequencerID = -1
+	leader.store = store
+
+	follower := &Service{
+		client:             leader.client,
+		sequencerList:      leader.sequencerList,
+		primarySequencerID: -1,
+		store:              store,
+	}
+
+	// Condition 1: 0 is primary, all is ready
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(i == 0)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+	}
+
+	// Situation 1: Maintenance mode is paused through the admin API of the leader
+	leader.SetMaintenance(true)
+
+	// Situation 2: The follower takes over leadership, should keep maintenance mode on
+	follower.bootstrap(context.Background(), log)
+
+	if !follower.Maintenance() || follower.PrimarySequencerID() != 0 {
+		t.Log("new leader should keep maintenance mode", follower.Maintenance(), follower.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 3: Maintenance mode is resumed through the admin API of the new leader, should be saved
+	follower.SetMaintenance(false)
+
+	if saved := follower.loadState(log); saved == nil || saved.Maintenance {
+		t.Log("maintenance mode should be saved as off", saved)
+
+		t.Fail()
+	}
+}
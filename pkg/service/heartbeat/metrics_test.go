@@ -0,0 +1,86 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rss3-network/vsl-reconcile/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+func TestSequencerMetrics(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 3)
+
+	endpoints := s.sequencerList
+
+	log := zap.NewNop()
+
+	// Condition 1: 0 is primary at block 100, 1 is a backup at block 98, 2 is a backup at block 90 and not ready
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(i == 0)
+
+		ms.SetIsReady(i != 2)
+
+		ms.SetUnsafeHash("unsafe-hash-100")
+	}
+
+	sequencers[0].SetUnsafeBlock(100, time.Now())
+
+	sequencers[1].SetUnsafeBlock(98, time.Now())
+
+	sequencers[1].SetSafeBlock(80)
+
+	sequencers[2].SetUnsafeBlock(90, time.Now())
+
+	s.transition(PhaseMonitoring, 0, log)
+
+	s.heartbeat(context.Background(), log)
+
+	// Situation 1: The heads of every sequencer are reported, not only of the primary one
+	for i, height := range []float64{100, 98, 90} {
+		if value := testutil.ToFloat64(metrics.UnsafeL2Height.WithLabelValues(endpoints[i])); value != height {
+			t.Log("unsafe l2 height mismatch", i, value)
+
+			t.Fail()
+		}
+	}
+
+	if value := testutil.ToFloat64(metrics.L1HeadLag.WithLabelValues(endpoints[1])); value > 5 {
+		t.Log("l1 head lag of a ready sequencer should be small", value)
+
+		t.Fail()
+	}
+
+	// The L1 head of a sequencer which is not ready is unknown, so it lags since the epoch
+	if value := testutil.ToFloat64(metrics.L1HeadLag.WithLabelValues(endpoints[2])); value < float64(time.Now().Unix()-5) {
+		t.Log("l1 head lag of a sequencer which is not ready should be large", value)
+
+		t.Fail()
+	}
+
+	if value := testutil.ToFloat64(metrics.SafeLag.WithLabelValues(endpoints[1])); value != 18 {
+		t.Log("safe lag mismatch", value)
+
+		t.Fail()
+	}
+
+	// Condition 2: The backup 1 moves on while the primary 0 keeps producing blocks
+	sequencers[0].SetUnsafeBlock(101, time.Now())
+
+	sequencers[1].SetUnsafeBlock(101, time.Now())
+
+	s.heartbeat(context.Background(), log)
+
+	// Situation 1: The backup heights are updated on every heartbeat
+	if value := testutil.ToFloat64(metrics.UnsafeL2Height.WithLabelValues(endpoints[1])); value != 101 {
+		t.Log("backup unsafe l2 height should be updated", value)
+
+		t.Fail()
+	}
+}
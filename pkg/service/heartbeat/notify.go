@@ -0,0 +1,30 @@
+package heartbeat
+
+import (
+	"context"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/internal/safe"
+	"github.com/rss3-network/vsl-reconcile/pkg/notify"
+	"go.uber.org/zap"
+)
+
+const notifyTimeout = 10 * time.Second
+
+// notify sends event in the background, so a slow receiver never holds up the heartbeat loop
+func (s *Service) notify(event notify.Event, log *zap.Logger) {
+	if s.notifier == nil {
+		return
+	}
+
+	event.Time = time.Now()
+
+	safe.Go(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+
+		if err := s.notifier.Notify(ctx, event); err != nil {
+			log.Error("Failed to send notification", zap.String("event", event.Event), zap.String("notifier", s.notifier.String()), zap.Error(err))
+		}
+	})
+}
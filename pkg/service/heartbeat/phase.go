@@ -0,0 +1,350 @@
+package heartbeat
+
+import (
+	"context"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// unreachableChecks is how many consecutive heartbeats must fail to reach the primary sequencer before it is switched over,
+// so a transient error never causes a switchover however short the maximum block time is
+const unreachableChecks = 3
+
+// Phase is the state of the heartbeat loop.
+type Phase int
+
+const (
+	// PhaseUnknown is the phase before the heartbeat loop is initialized, when no primary sequencer is known
+	PhaseUnknown Phase = iota
+	// PhaseMonitoring watches the primary sequencer producing blocks
+	PhaseMonitoring
+	// PhaseSwitching hands block production over to another sequencer, or promotes one if there is no primary
+	PhaseSwitching
+	// PhaseCoolingDown waits for a new primary sequencer to produce its first block
+	PhaseCoolingDown
+	// PhaseDegraded has no primary sequencer, as none could be activated,
+	// or keeps one which stopped or stalls, as the switchover budget is spent
+	PhaseDegraded
+	// PhaseBootstrapping finds or promotes the primary sequencer before the heartbeat loop starts
+	PhaseBootstrapping
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseMonitoring:
+		return "monitoring"
+	case PhaseSwitching:
+		return "switching"
+	case PhaseCoolingDown:
+		return "cooling_down"
+	case PhaseDegraded:
+		return "degraded"
+	case PhaseBootstrapping:
+		return "bootstrapping"
+	default:
+		return "unknown"
+	}
+}
+
+// Phase returns the phase of the heartbeat loop, without waiting for a heartbeat in progress.
+func (s *Service) Phase() Phase {
+	return Phase(s.phase.Load())
+}
+
+// transition moves the heartbeat loop to phase with id as the primary sequencer,
+// which restarts the block time tracking, s.mu must be held
+func (s *Service) transition(phase Phase, id int, log *zap.Logger) {
+	s.setPhase(phase, id, log)
+	s.setPrimary(id)
+}
+
+// setPhase moves the heartbeat loop to phase without changing the primary sequencer, s.mu must be held
+func (s *Service) setPhase(phase Phase, id int, log *zap.Logger) {
+	current := s.Phase()
+
+	if phase != current {
+		log.Info("Heartbeat phase changed",
+			zap.Stringer("from", current),
+			zap.Stringer("to", phase),
+			zap.Int("primary_sequencer_id", id),
+		)
+
+		switch {
+		case phase == PhaseDegraded:
+			s.degrade(id, log)
+		case current == PhaseDegraded:
+			s.recoverPrimary(id, log)
+		}
+	}
+
+	metrics.Phase.WithLabelValues(current.String()).Set(0)
+	metrics.Phase.WithLabelValues(phase.String()).Set(1)
+
+	s.phase.Store(int32(phase))
+}
+
+// handOver moves the heartbeat loop on after block production was handed over to id, -1 if it failed, s.mu must be held
+func (s *Service) handOver(id int, log *zap.Logger) {
+	if id == -1 {
+		s.transition(PhaseDegraded, -1, log)
+
+		return
+	}
+
+	s.transition(PhaseCoolingDown, id, log)
+}
+
+// heartbeat runs one step of the heartbeat loop, s.mu must be held
+func (s *Service) heartbeat(ctx context.Context, log *zap.Logger) {
+	defer s.publish()
+	defer s.saveState()
+
+	metrics.SecondsSinceLastBlock.Set(time.Since(s.currentBlockTime).Seconds())
+
+	s.refreshMaintenanceAnnotation(ctx, log)
+	s.refreshPriorities(ctx, log)
+	s.refreshExecutionEndpoints(ctx, log)
+
+	// Someone could have started another sequencer, which is fenced before the primary sequencer is checked
+	s.fenceSplitBrain(ctx, log)
+
+	s.trackSafeHead(s.observeSequencers(ctx, log), log)
+
+	switch s.Phase() {
+	case PhaseMonitoring:
+		s.monitor(ctx, log)
+	case PhaseCoolingDown:
+		s.coolDown(ctx, log)
+	case PhaseDegraded:
+		// Keep trying to promote a primary sequencer, the loop backs off between retries
+		s.degradedRetries++
+		metrics.DegradedRetries.Inc()
+
+		log.Info("Retrying promotion while degraded", zap.Int("retries", s.degradedRetries), zap.Duration("since", time.Since(s.degradedSince)))
+
+		s.switchPrimary(ctx, log)
+
+		return
+	}
+
+	// Block production is handed over in the same heartbeat as the primary sequencer failed
+	if s.Phase() == PhaseSwitching {
+		s.switchPrimary(ctx, log)
+	}
+}
+
+// monitor checks that the primary sequencer keeps producing blocks, s.mu must be held
+func (s *Service) monitor(ctx context.Context, log *zap.Logger) {
+	blockHeight, blockTime, ok := s.checkPrimary(ctx, log)
+	if !ok {
+		return
+	}
+
+	if blockHeight != s.currentBlockHeight {
+		s.currentBlockHeight = blockHeight
+		s.currentBlockTime = blockTime
+	}
+
+	// The primary sequencer is healthy, so block production can be handed back to a preferred one
+	s.failBack(ctx, log)
+}
+
+// coolDown waits for the new primary sequencer to produce a block after it was activated, s.mu must be held
+func (s *Service) coolDown(ctx context.Context, log *zap.Logger) {
+	_, blockTime, ok := s.checkPrimary(ctx, log)
+	if !ok || !blockTime.After(s.currentBlockTime) {
+		return
+	}
+
+	log.Info("New primary sequencer is producing blocks", zap.Int("sequencer_id", s.primarySequencerID))
+
+	s.transition(PhaseMonitoring, s.primarySequencerID, log)
+}
+
+// checkPrimary checks the primary sequencer is active and producing blocks, and returns its block height and time.
+// The heartbeat loop moves on to switching if it is not, s.mu must be held
+func (s *Service) checkPrimary(ctx context.Context, log *zap.Logger) (int64, time.Time, bool) {
+	if s.primarySequencerID == -1 {
+		s.transition(PhaseSwitching, -1, log)
+
+		return 0, time.Time{}, false
+	}
+
+	isActive, err := s.checkPrimarySequencerStatus(ctx, s.primarySequencerID)
+	if err != nil {
+		log.Error("Failed to check primary sequencer status", zap.Error(err))
+		s.switchUnreachablePrimary(log)
+
+		return 0, time.Time{}, false
+	}
+
+	if !isActive {
+		log.Info("Primary sequencer is not active, switching...")
+		s.transition(PhaseSwitching, s.primarySequencerID, log)
+
+		return 0, time.Time{}, false
+	}
+
+	blockHeight, blockTime, err := s.checkBlockHeight(ctx, s.primarySequencerID, log, s.currentBlockHeight, s.currentBlockTime)
+	if err != nil {
+		s.switchUnreachablePrimary(log)
+
+		return 0, time.Time{}, false
+	}
+
+	s.failedChecks = 0
+
+	if blockAge := time.Since(blockTime); blockAge > s.maxBlockTime {
+		log.Warn("Block time exceeds maximum tolerance, attempting to restart sequencer...", zap.Duration("block_age", blockAge))
+		s.transition(PhaseSwitching, s.primarySequencerID, log)
+
+		return 0, time.Time{}, false
+	}
+
+	if !s.checkPrimaryExecution(ctx, blockHeight, log) || !s.checkPrimaryHealth(ctx, log) {
+		// Flap damping, the primary sequencer still produces blocks and is checked again on the next heartbeat
+		if !s.deferSwitchover(log) {
+			s.transition(PhaseSwitching, s.primarySequencerID, log)
+		}
+
+		return 0, time.Time{}, false
+	}
+
+	return blockHeight, blockTime, true
+}
+
+// switchUnreachablePrimary moves on to switching once the primary sequencer could not be reached for unreachableChecks
+// consecutive heartbeats and longer than the maximum block time, as it could not have been seen producing blocks either, s.mu must be held
+func (s *Service) switchUnreachablePrimary(log *zap.Logger) {
+	s.failedChecks++
+
+	if blockAge := time.Since(s.currentBlockTime); s.failedChecks >= unreachableChecks && blockAge > s.maxBlockTime {
+		log.Warn("Primary sequencer is unreachable for longer than maximum block time, switching...",
+			zap.Duration("block_age", blockAge),
+			zap.Int("failed_checks", s.failedChecks),
+		)
+		s.transition(PhaseSwitching, s.primarySequencerID, log)
+	}
+}
+
+// checkPrimaryHealth runs the health checks against the primary sequencer, and reports whether it stays primary.
+// An unhealthy primary sequencer is only switched over if another sequencer is healthier, s.mu must be held
+func (s *Service) checkPrimaryHealth(ctx context.Context, log *zap.Logger) bool {
+	if len(s.healthChecks) == 0 {
+		return true
+	}
+
+	report, err := s.checkHealth(ctx, s.sequencerList[s.primarySequencerID])
+	if err != nil {
+		log.Error("Failed to check primary sequencer health", zap.Error(err))
+
+		return true
+	}
+
+	fields := []zap.Field{
+		zap.Int("sequencer_id", s.primarySequencerID),
+		zap.Float64("score", report.Score),
+		zap.Strings("reasons", report.Reasons()),
+	}
+
+	switch report.Health {
+	case HealthDegraded:
+		log.Warn("Primary sequencer is degraded", fields...)
+	case HealthUnhealthy:
+		if !s.healthierCandidate(ctx, report.Health, log) {
+			log.Warn("Primary sequencer is unhealthy, but no other sequencer is healthier", fields...)
+
+			return true
+		}
+
+		log.Warn("Primary sequencer is unhealthy, switching...", fields...)
+
+		return false
+	}
+
+	return true
+}
+
+// switchPrimary hands block production over to the next available sequencer,
+// or promotes one if no primary sequencer is tracked, s.mu must be held
+func (s *Service) switchPrimary(ctx context.Context, log *zap.Logger) {
+	if s.Maintenance() {
+		if s.primarySequencerID == -1 {
+			log.Warn("Maintenance mode, would promote new primary sequencer")
+
+			if id := s.firstActiveSequencer(ctx, log); id != -1 {
+				s.transition(PhaseMonitoring, id, log)
+			} else {
+				s.transition(PhaseDegraded, -1, log)
+			}
+
+			return
+		}
+
+		log.Warn("Maintenance mode, would deactivate primary sequencer and activate the next available one",
+			zap.Int("sequencer_id", s.primarySequencerID),
+			zap.String("sequencer", s.sequencerList[s.primarySequencerID]),
+		)
+
+		s.transition(PhaseMonitoring, s.primarySequencerID, log)
+
+		return
+	}
+
+	if s.primarySequencerID == -1 {
+		// A sequencer could have been started meanwhile, which is adopted instead of promoting a second one
+		if id := s.findActivePrimary(ctx, log); id != -1 {
+			s.transition(PhaseMonitoring, id, log)
+
+			return
+		}
+
+		// Promotion is retried on the next heartbeat
+		if s.holdPromotion(log) {
+			return
+		}
+
+		log.Info("No primary sequencer tracked, starting promotion process...")
+
+		primarySequencerID, err := s.promoteNewPrimary(ctx)
+		if err != nil {
+			log.Error("Failed to promote new primary sequencer", zap.Error(err))
+		}
+
+		s.handOver(primarySequencerID, log)
+
+		return
+	}
+
+	// The primary sequencer is checked again on the next heartbeat
+	if s.holdPromotion(log) {
+		s.setPhase(PhaseMonitoring, s.primarySequencerID, log)
+
+		return
+	}
+
+	// Switchovers away from a primary sequencer which stopped or stalls are damped as well, so two sequencers
+	// which keep stalling do not take turns. Once the budget is spent, the primary sequencer is kept while degraded
+	if s.deferSwitchover(log) {
+		if s.Phase() == PhaseDegraded || s.switchoverBudgetSpent(time.Now()) {
+			s.setPhase(PhaseDegraded, s.primarySequencerID, log)
+		} else {
+			s.setPhase(PhaseMonitoring, s.primarySequencerID, log)
+		}
+
+		return
+	}
+
+	currentSequencerID := s.primarySequencerID
+
+	primarySequencerID := s.switchSequencer(ctx, currentSequencerID, log)
+
+	// Only automatic switchovers count against the switchover budget and restart the cooldown
+	if primarySequencerID != -1 && primarySequencerID != currentSequencerID {
+		s.recordSwitchover(time.Now(), log)
+	}
+
+	s.handOver(primarySequencerID, log)
+}
@@ -0,0 +1,239 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestHeartbeatPhases(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 3)
+
+	log := zap.NewNop()
+
+	assertPhase := func(situation string, phase Phase, primarySequencerID int) {
+		if s.Phase() != phase || s.PrimarySequencerID() != primarySequencerID {
+			t.Log(situation, "phase or primary mismatch", s.Phase(), s.PrimarySequencerID())
+
+			t.Fail()
+		}
+
+		for i, ms := range sequencers {
+			if ms.GetIsActivated() != (i == primarySequencerID) {
+				t.Log(situation, "sequencer state is incorrect", i)
+
+				t.Fail()
+			}
+		}
+	}
+
+	// Condition 1: 0 is primary and producing blocks, all is ready
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(i == 0)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+
+		ms.SetUnsafeBlock(10, time.Now())
+	}
+
+	s.transition(PhaseMonitoring, 0, log)
+
+	// Situation 1: Should keep monitoring 0
+	s.heartbeat(context.Background(), log)
+
+	assertPhase("situation 1", PhaseMonitoring, 0)
+
+	if s.currentBlockHeight != 10 {
+		t.Log("block height is not tracked", s.currentBlockHeight)
+
+		t.Fail()
+	}
+
+	// Situation 2: 0 stalls and can not be restarted, should switch to 1 and cool down with a new stall timer
+	sequencers[0].SetIsReady(false)
+
+	sequencers[0].SetUnsafeBlock(10, time.Now().Add(-time.Hour))
+
+	s.currentBlockTime = time.Now().Add(-time.Hour)
+
+	s.heartbeat(context.Background(), log)
+
+	assertPhase("situation 2", PhaseCoolingDown, 1)
+
+	if time.Since(s.currentBlockTime) > time.Second {
+		t.Log("stall timer is not reset", s.currentBlockTime)
+
+		t.Fail()
+	}
+
+	// Situation 3: 1 has not produced a block yet, should keep cooling down
+	sequencers[1].SetUnsafeBlock(10, time.Now().Add(-time.Hour))
+
+	s.heartbeat(context.Background(), log)
+
+	assertPhase("situation 3", PhaseCoolingDown, 1)
+
+	// Situation 4: 1 produces a block, should monitor 1
+	sequencers[1].SetUnsafeBlock(11, time.Now().Add(2*time.Second))
+
+	s.heartbeat(context.Background(), log)
+
+	assertPhase("situation 4", PhaseMonitoring, 1)
+
+	// Situation 5: 1 is stopped and no sequencer is ready, should be degraded
+	for _, ms := range sequencers {
+		ms.SetIsReady(false)
+	}
+
+	sequencers[1].SetIsActivated(false)
+
+	s.heartbeat(context.Background(), log)
+
+	assertPhase("situation 5", PhaseDegraded, -1)
+
+	// Situation 6: Still no sequencer is ready, should stay degraded
+	s.heartbeat(context.Background(), log)
+
+	assertPhase("situation 6", PhaseDegraded, -1)
+
+	// Situation 7: 2 is ready, should promote 2 and cool down
+	sequencers[2].SetIsReady(true)
+
+	s.heartbeat(context.Background(), log)
+
+	assertPhase("situation 7", PhaseCoolingDown, 2)
+
+	// Situation 8: 2 produces no block for longer than max block time, should switch to 0
+	sequencers[0].SetIsReady(true)
+
+	sequencers[2].SetIsReady(false)
+
+	sequencers[2].SetUnsafeBlock(11, time.Now().Add(-time.Hour))
+
+	s.currentBlockTime = time.Now().Add(-time.Hour)
+
+	s.heartbeat(context.Background(), log)
+
+	assertPhase("situation 8", PhaseCoolingDown, 0)
+
+	// Situation 9: Primary sequencer is removed, should promote another one
+	s.updateSequencerList(s.sequencerList[1:])
+
+	if s.Phase() != PhaseSwitching || s.PrimarySequencerID() != -1 {
+		t.Log("situation 9", "phase or primary mismatch", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	sequencers[0].SetIsActivated(false)
+
+	sequencers[1].SetIsReady(true)
+
+	s.heartbeat(context.Background(), log)
+
+	if s.Phase() != PhaseCoolingDown || s.PrimarySequencerID() != 0 || !sequencers[1].GetIsActivated() {
+		t.Log("situation 9", "phase or primary mismatch", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+}
+
+func TestSwitchPrimaryAdoptsActiveSequencer(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 3)
+
+	log := zap.NewNop()
+
+	// Condition 1: No primary is tracked, but 2 was started meanwhile, all is ready
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(i == 2)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+	}
+
+	s.transition(PhaseSwitching, -1, log)
+
+	s.switchPrimary(context.Background(), log)
+
+	// Situation 1: Should monitor 2 without promoting another sequencer
+	if s.Phase() != PhaseMonitoring || s.PrimarySequencerID() != 2 {
+		t.Log("should adopt 2", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	for i, ms := range sequencers {
+		if ms.GetIsActivated() != (i == 2) {
+			t.Log("sequencer state is incorrect", i)
+
+			t.Fail()
+		}
+	}
+}
+
+func TestUnreachablePrimary(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 2)
+
+	log := zap.NewNop()
+
+	// Condition 1: 0 is primary and can not be reached, 1 is ready
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(i == 0)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+	}
+
+	s.transition(PhaseMonitoring, 0, log)
+
+	sequencers[0].Close()
+
+	// Situation 1: Unreachable within the maximum block time, should keep monitoring 0
+	s.heartbeat(context.Background(), log)
+
+	if s.Phase() != PhaseMonitoring || s.PrimarySequencerID() != 0 {
+		t.Log("should keep 0", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 2: Unreachable for longer than the maximum block time, but fewer than unreachableChecks heartbeats, should keep 0
+	s.currentBlockTime = time.Now().Add(-time.Hour)
+
+	for range unreachableChecks - 2 {
+		s.heartbeat(context.Background(), log)
+	}
+
+	if s.Phase() != PhaseMonitoring || s.PrimarySequencerID() != 0 {
+		t.Log("should not switch on a transient error", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 3: Unreachable for unreachableChecks heartbeats, should switch to 1
+	s.heartbeat(context.Background(), log)
+
+	if s.Phase() != PhaseCoolingDown || s.PrimarySequencerID() != 1 || !sequencers[1].GetIsActivated() {
+		t.Log("should switch to 1", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+}
@@ -0,0 +1,107 @@
+package heartbeat
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/discovery"
+	"go.uber.org/zap"
+)
+
+// priority is the priority of a sequencer, higher is preferred.
+// Pod annotations take precedence over the configured priorities, which match the endpoint or the pod name, s.mu must be held
+func (s *Service) priority(sequencer string) int {
+	if priority, ok := s.annotatedPriorities[sequencer]; ok {
+		return priority
+	}
+
+	if priority, ok := s.priorities[sequencer]; ok {
+		return priority
+	}
+
+	// Sequencers discovered from a StatefulSet are reached at <pod>.<service>.<namespace>.svc...
+	if u, err := url.Parse(sequencer); err == nil {
+		podName, _, _ := strings.Cut(u.Hostname(), ".")
+
+		if priority, ok := s.priorities[podName]; ok {
+			return priority
+		}
+	}
+
+	return 0
+}
+
+// candidates orders the sequencers to activate by priority, starting from id among equal priorities, s.mu must be held
+func (s *Service) candidates(id int) []int {
+	candidates := make([]int, 0, len(s.sequencerList))
+
+	for i := 0; i < len(s.sequencerList); i++ {
+		candidates = append(candidates, (i+id)%len(s.sequencerList))
+	}
+
+	slices.SortStableFunc(candidates, func(a, b int) int {
+		return s.priority(s.sequencerList[b]) - s.priority(s.sequencerList[a])
+	})
+
+	return candidates
+}
+
+// refreshPriorities reads the priority annotations of the sequencers, s.mu must be held
+func (s *Service) refreshPriorities(ctx context.Context, log *zap.Logger) {
+	prioritizer, ok := s.discoverer.(discovery.Prioritizer)
+	if !ok {
+		return
+	}
+
+	priorities, err := prioritizer.Priorities(ctx)
+	if err != nil {
+		log.Error("Failed to get sequencer priorities", zap.Error(err))
+
+		return
+	}
+
+	s.annotatedPriorities = priorities
+}
+
+// failBack hands block production over to the most preferred ready sequencer,
+// if it is preferred over the primary sequencer, s.mu must be held
+func (s *Service) failBack(ctx context.Context, log *zap.Logger) {
+	if !s.failback || s.Maintenance() || s.primarySequencerID == -1 {
+		return
+	}
+
+	primaryPriority := s.priority(s.sequencerList[s.primarySequencerID])
+
+	for _, id := range s.candidates(s.primarySequencerID) {
+		sequencer := s.sequencerList[id]
+
+		if s.priority(sequencer) <= primaryPriority {
+			// Candidates are ordered by priority, so no other sequencer is preferred
+			return
+		}
+
+		_, _, isReady, err := s.client.GetOPSyncStatus(ctx, sequencer)
+		if err != nil || !isReady {
+			continue
+		}
+
+		if s.holdPromotion(log) || s.deferSwitchover(log) {
+			return
+		}
+
+		log.Info("Failing back to preferred sequencer",
+			zap.Int("sequencer_id", s.primarySequencerID),
+			zap.Int("target_id", id),
+			zap.String("target", sequencer),
+		)
+
+		// The handover is never abandoned halfway, as the primary would be left stopped
+		if _, err := s.switchover(context.WithoutCancel(ctx), id, log); err != nil {
+			log.Error("Failed to fail back to preferred sequencer", zap.Int("target_id", id), zap.Error(err))
+		}
+
+		return
+	}
+}
@@ -0,0 +1,90 @@
+package heartbeat
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestPriority(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 3)
+
+	log := zap.NewNop()
+
+	endpoints := s.sequencerList
+
+	// 2 is preferred, then 1, 0 is the last resort
+	s.priorities = map[string]int{
+		endpoints[1]: 5,
+		endpoints[2]: 10,
+	}
+
+	// Situation 1: Candidates are ordered by priority
+	if candidates := s.candidates(0); !slices.Equal(candidates, []int{2, 1, 0}) {
+		t.Log("candidates mismatch", candidates)
+
+		t.Fail()
+	}
+
+	// Condition 1: all is ready
+	for _, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(false)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+
+		ms.SetUnsafeBlock(10, time.Now())
+	}
+
+	// Situation 1: Should promote 2
+	if id, _ := s.promoteNewPrimary(context.Background()); id != 2 {
+		t.Log("should promote the preferred sequencer", id)
+
+		t.Fail()
+	}
+
+	// Condition 2: 0 is primary and producing blocks, 2 is not ready and 1 is ready
+	for i, ms := range sequencers {
+		ms.SetIsActivated(i == 0)
+	}
+
+	sequencers[2].SetIsReady(false)
+
+	s.transition(PhaseMonitoring, 0, log)
+
+	// Situation 1: Should keep 0 without fail-back
+	s.heartbeat(context.Background(), log)
+
+	if s.Phase() != PhaseMonitoring || s.PrimarySequencerID() != 0 {
+		t.Log("should keep 0", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	// Situation 2: Should fail back to 1 with fail-back
+	s.failback = true
+
+	s.heartbeat(context.Background(), log)
+
+	if s.Phase() != PhaseCoolingDown || s.PrimarySequencerID() != 1 {
+		t.Log("should fail back to 1", s.Phase(), s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	for i, ms := range sequencers {
+		if ms.GetIsActivated() != (i == 1) {
+			t.Log("sequencer state is incorrect", i)
+
+			t.Fail()
+		}
+	}
+}
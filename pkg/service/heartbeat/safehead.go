@@ -0,0 +1,144 @@
+package heartbeat
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/internal/rpc"
+	"github.com/rss3-network/vsl-reconcile/pkg/metrics"
+	"github.com/rss3-network/vsl-reconcile/pkg/notify"
+	"go.uber.org/zap"
+)
+
+// SafeHeadStatus is the view of the safe head progress, which stops when op-batcher is broken.
+type SafeHeadStatus struct {
+	Number     int64      `json:"number"`
+	AdvancedAt *time.Time `json:"advanced_at,omitempty"`
+	Stalled    bool       `json:"stalled"`
+}
+
+// trackSafeHead takes the highest safe and unsafe heads of the sequencers, and reports the safe head as stalled
+// once it has not advanced for the stall timeout while there are unsafe blocks to batch, s.mu must be held
+func (s *Service) trackSafeHead(statuses []*rpc.SyncStatus, log *zap.Logger) {
+	if s.safeHeadStallTimeout == 0 {
+		return
+	}
+
+	var (
+		safeNumber   int64
+		unsafeNumber int64
+		known        bool
+	)
+
+	for _, syncStatus := range statuses {
+		if syncStatus == nil || syncStatus.SafeL2.Hash == "" {
+			continue
+		}
+
+		safeNumber = max(safeNumber, syncStatus.SafeL2.Number)
+		unsafeNumber = max(unsafeNumber, syncStatus.UnsafeL2.Number)
+		known = true
+	}
+
+	if !known {
+		return
+	}
+
+	now := time.Now()
+
+	// The safe head is not stalled if it has caught up, as there is nothing to batch
+	if s.safeHeadTime.IsZero() || safeNumber > s.safeHeadNumber || safeNumber >= unsafeNumber {
+		if s.safeHeadStalled {
+			s.recoverSafeHead(safeNumber, now, log)
+		}
+
+		s.safeHeadNumber = max(s.safeHeadNumber, safeNumber)
+		s.safeHeadTime = now
+	} else if !s.safeHeadStalled && now.Sub(s.safeHeadTime) >= s.safeHeadStallTimeout {
+		s.stallSafeHead(unsafeNumber, now, log)
+	}
+
+	metrics.SecondsSinceSafeHead.Set(now.Sub(s.safeHeadTime).Seconds())
+}
+
+// stallSafeHead reports the safe head as stalled, s.mu must be held
+func (s *Service) stallSafeHead(unsafeNumber int64, now time.Time, log *zap.Logger) {
+	s.safeHeadStalled = true
+
+	metrics.SafeHeadStalled.Set(1)
+
+	duration := now.Sub(s.safeHeadTime).Round(time.Second)
+
+	log.Error("Safe head stalled, op-batcher may be broken",
+		zap.String("event", "safe_head_stalled"),
+		zap.Int64("safe_l2_number", s.safeHeadNumber),
+		zap.Int64("unsafe_l2_number", unsafeNumber),
+		zap.Duration("duration", duration),
+		zap.Bool("hold_promotion", s.safeHeadHold),
+	)
+
+	s.notify(notify.Event{
+		Event: notify.EventSafeHeadStalled,
+		Text: fmt.Sprintf("Safe head has not advanced from %d for %s while the unsafe head is at %d, op-batcher may be broken and unsafe blocks may be reorged",
+			s.safeHeadNumber, duration, unsafeNumber),
+		Fields: map[string]string{
+			"safe_number":   strconv.FormatInt(s.safeHeadNumber, 10),
+			"unsafe_number": strconv.FormatInt(unsafeNumber, 10),
+			"duration":      duration.String(),
+		},
+	}, log)
+}
+
+// recoverSafeHead reports the safe head advancing again after it stalled, s.mu must be held
+func (s *Service) recoverSafeHead(safeNumber int64, now time.Time, log *zap.Logger) {
+	s.safeHeadStalled = false
+
+	metrics.SafeHeadStalled.Set(0)
+
+	duration := now.Sub(s.safeHeadTime).Round(time.Second)
+
+	log.Info("Safe head advancing again", zap.Int64("safe_l2_number", safeNumber), zap.Duration("duration", duration))
+
+	s.notify(notify.Event{
+		Event: notify.EventSafeHeadRecovered,
+		Text:  fmt.Sprintf("Safe head is advancing again at %d after %s", safeNumber, duration),
+		Fields: map[string]string{
+			"safe_number": strconv.FormatInt(safeNumber, 10),
+			"duration":    duration.String(),
+		},
+	}, log)
+}
+
+// holdPromotion reports whether automatic switchovers and promotions are held, as they would extend
+// an unsafe chain which may be reorged while the safe head is stalled, s.mu must be held
+func (s *Service) holdPromotion(log *zap.Logger) bool {
+	if !s.safeHeadHold || !s.safeHeadStalled {
+		return false
+	}
+
+	metrics.SwitchoversDeferred.WithLabelValues("safe_head_stalled").Inc()
+
+	log.Warn("Switchover deferred",
+		zap.String("event", "switchover_deferred"),
+		zap.String("reason", "safe_head_stalled"),
+		zap.Int64("safe_l2_number", s.safeHeadNumber),
+	)
+
+	return true
+}
+
+// safeHeadStatus reports the safe head progress, nil if it is not tracked, s.mu must be held
+func (s *Service) safeHeadStatus() *SafeHeadStatus {
+	if s.safeHeadTime.IsZero() {
+		return nil
+	}
+
+	advancedAt := s.safeHeadTime
+
+	return &SafeHeadStatus{
+		Number:     s.safeHeadNumber,
+		AdvancedAt: &advancedAt,
+		Stalled:    s.safeHeadStalled,
+	}
+}
@@ -0,0 +1,106 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/notify"
+	"go.uber.org/zap"
+)
+
+func TestSafeHead(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 2)
+
+	log := zap.NewNop()
+
+	notifier := &testNotifier{events: make(chan notify.Event, 4)}
+
+	s.safeHeadStallTimeout = time.Hour
+	s.safeHeadHold = true
+	s.notifier = notifier
+
+	// Condition 1: 0 is primary with its safe head 10 blocks behind
+	for _, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-100")
+
+		ms.SetUnsafeBlock(100, time.Now())
+
+		ms.SetSafeBlock(90)
+	}
+
+	sequencers[0].SetIsActivated(true)
+
+	s.transition(PhaseMonitoring, 0, log)
+
+	s.heartbeat(context.Background(), log)
+
+	// Situation 1: The safe head is tracked
+	if status := s.Status(context.Background()); status.SafeHead == nil || status.SafeHead.Number != 90 || status.SafeHead.Stalled {
+		t.Log("safe head should be tracked", status.SafeHead)
+
+		t.Fail()
+	}
+
+	// Condition 2: The safe head has not advanced for longer than the stall timeout
+	s.safeHeadTime = time.Now().Add(-2 * time.Hour)
+
+	s.heartbeat(context.Background(), log)
+
+	// Situation 1: Should be stalled and notify
+	if !s.safeHeadStalled {
+		t.Log("safe head should be stalled")
+
+		t.Fail()
+	}
+
+	if event := <-notifier.events; event.Event != notify.EventSafeHeadStalled || event.Fields["safe_number"] != "90" {
+		t.Log("should notify safe head stalled", event)
+
+		t.Fail()
+	}
+
+	// Condition 3: 0 stops producing blocks
+	sequencers[0].SetIsActivated(false)
+
+	s.heartbeat(context.Background(), log)
+
+	// Situation 1: The switchover is held while the safe head is stalled
+	if s.PrimarySequencerID() != 0 || s.Phase() != PhaseMonitoring || sequencers[1].GetIsActivated() {
+		t.Log("switchover should be held", s.PrimarySequencerID(), s.Phase())
+
+		t.Fail()
+	}
+
+	// Condition 4: The safe head advances again
+	for _, ms := range sequencers {
+		ms.SetSafeBlock(95)
+	}
+
+	s.heartbeat(context.Background(), log)
+
+	// Situation 1: Should recover, notify and hand block production over
+	if s.safeHeadStalled {
+		t.Log("safe head should not be stalled")
+
+		t.Fail()
+	}
+
+	if event := <-notifier.events; event.Event != notify.EventSafeHeadRecovered || event.Fields["safe_number"] != "95" {
+		t.Log("should notify safe head recovered", event)
+
+		t.Fail()
+	}
+
+	if id := s.PrimarySequencerID(); id == -1 || s.Phase() != PhaseCoolingDown || !sequencers[id].GetIsActivated() {
+		t.Log("should hand block production over", id, s.Phase())
+
+		t.Fail()
+	}
+}
@@ -0,0 +1,178 @@
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/internal/rpc"
+	"github.com/rss3-network/vsl-reconcile/pkg/metrics"
+	"github.com/rss3-network/vsl-reconcile/pkg/notify"
+	"go.uber.org/zap"
+)
+
+// maxIncidents is how many split-brain incidents are kept for the status API
+const maxIncidents = 10
+
+// Incident is a split-brain incident, where more than one sequencer was producing blocks.
+type Incident struct {
+	Time          time.Time `json:"time"`
+	Active        []string  `json:"active"`
+	Authoritative string    `json:"authoritative"`
+	Fenced        []string  `json:"fenced"`
+}
+
+// activeSequencer is a sequencer found active while checking for split-brain.
+type activeSequencer struct {
+	id         int
+	syncStatus *rpc.SyncStatus
+}
+
+// fenceSplitBrain polls all sequencers concurrently, and if more than one is active,
+// stops every one but the authoritative one, whose unsafe head is the highest, s.mu must be held
+func (s *Service) fenceSplitBrain(ctx context.Context, log *zap.Logger) {
+	active := s.activeSequencers(ctx)
+	if len(active) < 2 {
+		return
+	}
+
+	// The tracked primary is authoritative among equal unsafe heads, then the first one
+	authoritative := active[0]
+
+	for _, candidate := range active[1:] {
+		switch {
+		case candidate.syncStatus.UnsafeL2.Number > authoritative.syncStatus.UnsafeL2.Number:
+			authoritative = candidate
+		case candidate.syncStatus.UnsafeL2.Number == authoritative.syncStatus.UnsafeL2.Number && candidate.id == s.primarySequencerID:
+			authoritative = candidate
+		}
+	}
+
+	metrics.SplitBrainIncidents.Inc()
+
+	incident := Incident{
+		Time:          time.Now(),
+		Authoritative: s.sequencerList[authoritative.id],
+	}
+
+	for _, a := range active {
+		incident.Active = append(incident.Active, s.sequencerList[a.id])
+	}
+
+	log.Error("Multiple active sequencers detected",
+		zap.String("event", "split_brain"),
+		zap.Strings("active", incident.Active),
+		zap.String("authoritative", incident.Authoritative),
+		zap.Int64("unsafe_l2_number", authoritative.syncStatus.UnsafeL2.Number),
+	)
+
+	for _, a := range active {
+		if a.id == authoritative.id {
+			continue
+		}
+
+		sequencer := s.sequencerList[a.id]
+
+		if s.Maintenance() {
+			log.Warn("Maintenance mode, would fence sequencer", zap.Int("sequencer_id", a.id), zap.String("sequencer", sequencer))
+
+			continue
+		}
+
+		if _, err := s.client.DeactivateSequencer(ctx, sequencer); err != nil {
+			log.Error("Failed to fence sequencer", zap.Int("sequencer_id", a.id), zap.String("sequencer", sequencer), zap.Error(err))
+
+			continue
+		}
+
+		log.Warn("Sequencer fenced",
+			zap.String("event", "sequencer_fenced"),
+			zap.Int("sequencer_id", a.id),
+			zap.String("sequencer", sequencer),
+			zap.Int64("unsafe_l2_number", a.syncStatus.UnsafeL2.Number),
+		)
+
+		incident.Fenced = append(incident.Fenced, sequencer)
+	}
+
+	s.recordIncident(incident)
+
+	s.notify(notify.Event{
+		Event: notify.EventSplitBrain,
+		Text: fmt.Sprintf("%d sequencers were active at the same time, %s is authoritative and %d were fenced",
+			len(incident.Active), incident.Authoritative, len(incident.Fenced)),
+		Fields: map[string]string{
+			"active":        strings.Join(incident.Active, ","),
+			"authoritative": incident.Authoritative,
+			"fenced":        strings.Join(incident.Fenced, ","),
+			"unsafe_number": strconv.FormatInt(authoritative.syncStatus.UnsafeL2.Number, 10),
+		},
+	}, log)
+
+	// Track the authoritative sequencer, as the tracked primary has been fenced or there was none
+	if authoritative.id != s.primarySequencerID && !s.Maintenance() {
+		log.Info("Tracking authoritative sequencer as primary", zap.Int("sequencer_id", authoritative.id))
+
+		s.transition(PhaseMonitoring, authoritative.id, log)
+	}
+}
+
+// activeSequencers polls all sequencers concurrently, and returns the active ones with their sync status, s.mu must be held
+func (s *Service) activeSequencers(ctx context.Context) []activeSequencer {
+	statuses := make([]*activeSequencer, len(s.sequencerList))
+
+	var wg sync.WaitGroup
+
+	for id, sequencer := range s.sequencerList {
+		wg.Add(1)
+
+		go func(id int, sequencer string) {
+			defer wg.Done()
+
+			isActive, err := s.client.CheckSequencerActive(ctx, sequencer)
+			if err != nil {
+				s.recordError(sequencer, err)
+
+				return
+			}
+
+			if !isActive {
+				return
+			}
+
+			syncStatus, err := s.client.GetSyncStatus(ctx, sequencer)
+			if err != nil {
+				s.recordError(sequencer, err)
+
+				// The unsafe head is unknown, so the sequencer is never authoritative
+				syncStatus = &rpc.SyncStatus{UnsafeL2: rpc.BlockRef{Number: -1}}
+			}
+
+			statuses[id] = &activeSequencer{id: id, syncStatus: syncStatus}
+		}(id, sequencer)
+	}
+
+	wg.Wait()
+
+	var active []activeSequencer
+
+	for _, status := range statuses {
+		if status != nil {
+			active = append(active, *status)
+		}
+	}
+
+	return active
+}
+
+// recordIncident keeps the latest split-brain incidents, s.mu must be held
+func (s *Service) recordIncident(incident Incident) {
+	s.incidents = append(s.incidents, incident)
+
+	if len(s.incidents) > maxIncidents {
+		s.incidents = s.incidents[len(s.incidents)-maxIncidents:]
+	}
+}
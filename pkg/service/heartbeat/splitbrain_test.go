@@ -0,0 +1,78 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/notify"
+	"go.uber.org/zap"
+)
+
+func TestSplitBrain(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 3)
+
+	endpoints := s.sequencerList
+
+	log := zap.NewNop()
+
+	notifier := &testNotifier{events: make(chan notify.Event, 4)}
+
+	s.notifier = notifier
+
+	// Condition 1: 0 is primary at block 10, 2 is started manually at block 8
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(i != 1)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+
+		ms.SetUnsafeBlock(10, time.Now())
+	}
+
+	sequencers[2].SetUnsafeBlock(8, time.Now())
+
+	s.transition(PhaseMonitoring, 0, log)
+
+	// Situation 1: Should fence 2 and keep 0
+	s.heartbeat(context.Background(), log)
+
+	if s.PrimarySequencerID() != 0 || !sequencers[0].GetIsActivated() || sequencers[2].GetIsActivated() {
+		t.Log("should fence 2", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	if event := <-notifier.events; event.Event != notify.EventSplitBrain || event.Fields["fenced"] != endpoints[2] {
+		t.Log("should notify split-brain", event)
+
+		t.Fail()
+	}
+
+	// Condition 2: 1 is started manually and ahead of 0
+	sequencers[1].SetIsActivated(true)
+
+	sequencers[1].SetUnsafeBlock(12, time.Now())
+
+	// Situation 1: Should fence 0 and track 1
+	s.heartbeat(context.Background(), log)
+
+	if s.PrimarySequencerID() != 1 || sequencers[0].GetIsActivated() || !sequencers[1].GetIsActivated() {
+		t.Log("should fence 0 and track 1", s.PrimarySequencerID())
+
+		t.Fail()
+	}
+
+	incidents := s.Status(context.Background()).Incidents
+
+	if len(incidents) != 2 || incidents[1].Authoritative != endpoints[1] || len(incidents[1].Fenced) != 1 || incidents[1].Fenced[0] != endpoints[0] {
+		t.Log("incidents mismatch", incidents)
+
+		t.Fail()
+	}
+}
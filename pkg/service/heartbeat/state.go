@@ -0,0 +1,116 @@
+package heartbeat
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/state"
+	"go.uber.org/zap"
+)
+
+// loadState loads the state saved before the last restart, nil if there is none
+func (s *Service) loadState(log *zap.Logger) *state.State {
+	if s.store == nil {
+		return nil
+	}
+
+	saved, err := s.store.Load(context.Background())
+	if err != nil {
+		log.Error("Failed to load state", zap.Error(err), zap.String("store", s.store.String()))
+
+		return nil
+	}
+
+	return saved
+}
+
+// resumePrimary finds the saved primary sequencer, if it is still discovered and active, s.mu must be held
+func (s *Service) resumePrimary(ctx context.Context, saved *state.State, log *zap.Logger) int {
+	if saved == nil || saved.Primary == "" {
+		return -1
+	}
+
+	id := slices.Index(s.sequencerList, saved.Primary)
+	if id == -1 {
+		log.Info("Saved primary sequencer is not discovered", zap.String("sequencer", saved.Primary))
+
+		return -1
+	}
+
+	isActive, err := s.client.CheckSequencerActive(ctx, saved.Primary)
+	if err != nil {
+		log.Error("Failed to get saved primary sequencer status", zap.String("sequencer", saved.Primary), zap.Error(err))
+
+		return -1
+	}
+
+	if !isActive {
+		log.Info("Saved primary sequencer is not active", zap.String("sequencer", saved.Primary))
+
+		return -1
+	}
+
+	log.Info("Resuming saved primary sequencer", zap.Int("id", id), zap.String("sequencer", saved.Primary))
+
+	return id
+}
+
+// restoreState tracks the saved block height of the resumed primary sequencer. The saved block time is only as recent
+// as the last state change, so the stall timer restarts from now, rather than switching on the first failed check, s.mu must be held
+func (s *Service) restoreState(saved *state.State) {
+	if saved == nil {
+		return
+	}
+
+	s.lastSwitchover = saved.LastSwitchover
+
+	if s.primarySequencerID == -1 || s.sequencerList[s.primarySequencerID] != saved.Primary {
+		return
+	}
+
+	s.currentBlockHeight = saved.BlockHeight
+	s.currentBlockTime = time.Now()
+
+	// The primary sequencer is unchanged, but the restored block height is saved over the reset one
+	s.stateSaved = false
+	s.saveState()
+}
+
+// saveState saves the tracked primary sequencer once it, the phase or maintenance mode changed, s.mu must be held
+func (s *Service) saveState() {
+	if s.store == nil {
+		return
+	}
+
+	maintenance := s.maintenance.Load()
+
+	if s.stateSaved && s.savedPrimarySequencerID == s.primarySequencerID && s.savedPhase == s.Phase() && s.savedMaintenance == maintenance {
+		return
+	}
+
+	current := &state.State{
+		BlockHeight:    s.currentBlockHeight,
+		BlockTime:      s.currentBlockTime,
+		LastSwitchover: s.lastSwitchover,
+		Maintenance:    maintenance,
+	}
+
+	if s.primarySequencerID != -1 {
+		current.Primary = s.sequencerList[s.primarySequencerID]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.store.Save(ctx, current); err != nil {
+		zap.L().Error("Failed to save state", zap.String("service", "heartbeat"), zap.Error(err), zap.String("store", s.store.String()))
+
+		return
+	}
+
+	s.stateSaved = true
+	s.savedPrimarySequencerID = s.primarySequencerID
+	s.savedPhase = s.Phase()
+	s.savedMaintenance = maintenance
+}
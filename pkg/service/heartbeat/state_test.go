@@ -0,0 +1,100 @@
+package heartbeat
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/state"
+	"go.uber.org/zap"
+)
+
+func TestState(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 3)
+
+	endpoints := s.sequencerList
+
+	log := zap.NewNop()
+
+	store := state.NewFile(filepath.Join(t.TempDir(), "state.json"))
+
+	s.primarySequencerID = -1
+	s.store = store
+
+	// Condition 1: 1 is primary, all is ready
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(i == 1)
+
+		ms.SetIsReady(true)
+	}
+
+	blockTime := time.Now().Add(-time.Minute).Round(0)
+
+	err := store.Save(context.Background(), &state.State{
+		Primary:     endpoints[1],
+		BlockHeight: 100,
+		BlockTime:   blockTime,
+	})
+
+	if err != nil {
+		t.Fatal("failed to save state", err)
+	}
+
+	// Situation 1: Should resume 1 with its block height, restarting the stall timer from now
+	saved := s.loadState(log)
+
+	resumeTime := time.Now()
+
+	s.setPrimary(s.resumePrimary(context.Background(), saved, log))
+	s.restoreState(saved)
+
+	if s.primarySequencerID != 1 {
+		t.Log("resumed wrong sequencer", s.primarySequencerID)
+
+		t.Fail()
+	}
+
+	if s.currentBlockHeight != 100 || s.currentBlockTime.Before(resumeTime) {
+		t.Log("block tracking is not restored", s.currentBlockHeight, s.currentBlockTime)
+
+		t.Fail()
+	}
+
+	// Situation 2: Switched to 2, should save 2 with the switchover time
+	s.recordSwitchover(time.Now(), log)
+
+	s.setPrimary(2)
+
+	saved = s.loadState(log)
+
+	if saved.Primary != endpoints[2] || saved.LastSwitchover.IsZero() {
+		t.Log("state is not saved", saved)
+
+		t.Fail()
+	}
+
+	// Situation 3: 2 produces a block, should not save again as neither the primary nor the phase changed
+	s.currentBlockHeight = 200
+
+	s.saveState()
+
+	if saved = s.loadState(log); saved.BlockHeight == 200 {
+		t.Log("state is saved on every block", saved)
+
+		t.Fail()
+	}
+
+	// Situation 4: Saved primary is no longer active, should not resume
+	s.primarySequencerID = -1
+
+	if id := s.resumePrimary(context.Background(), saved, log); id != -1 {
+		t.Log("resumed inactive sequencer", id)
+
+		t.Fail()
+	}
+}
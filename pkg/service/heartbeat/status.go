@@ -0,0 +1,273 @@
+package heartbeat
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ClusterStatus is the view of all discovered sequencers.
+type ClusterStatus struct {
+	Phase              string `json:"phase"`
+	PrimarySequencerID int    `json:"primary_sequencer_id"`
+	Maintenance        bool   `json:"maintenance"`
+
+	// DegradedSince is when no sequencer could be activated, only set while degraded
+	DegradedSince   *time.Time `json:"degraded_since,omitempty"`
+	DegradedRetries int        `json:"degraded_retries,omitempty"`
+
+	Damping DampingStatus `json:"damping"`
+
+	// SafeHead is only reported if the safe head is tracked
+	SafeHead *SafeHeadStatus `json:"safe_head,omitempty"`
+
+	// Incidents are the latest split-brain incidents
+	Incidents []Incident `json:"incidents,omitempty"`
+
+	Sequencers []SequencerStatus `json:"sequencers"`
+}
+
+// DampingStatus is the view of the flap damping of automatic switchovers.
+type DampingStatus struct {
+	LastSwitchover        *time.Time `json:"last_switchover,omitempty"`
+	CooldownUntil         *time.Time `json:"cooldown_until,omitempty"`
+	SwitchoversLastHour   int        `json:"switchovers_last_hour"`
+	MaxSwitchoversPerHour int        `json:"max_switchovers_per_hour"`
+}
+
+// SequencerStatus is the view of a single sequencer.
+type SequencerStatus struct {
+	ID       int    `json:"id"`
+	Endpoint string `json:"endpoint"`
+	Primary  bool   `json:"primary"`
+	Priority int    `json:"priority"`
+	Active   bool   `json:"active"`
+	Ready    bool   `json:"ready"`
+
+	// ExecutionEndpoint is the paired op-geth, if it is known
+	ExecutionEndpoint string `json:"execution_endpoint,omitempty"`
+
+	UnsafeL2Number    int64  `json:"unsafe_l2_number"`
+	UnsafeL2Hash      string `json:"unsafe_l2_hash"`
+	UnsafeL2Timestamp int64  `json:"unsafe_l2_timestamp"`
+	SafeL2Number      int64  `json:"safe_l2_number"`
+	FinalizedL2Number int64  `json:"finalized_l2_number"`
+	L1HeadTimestamp   int64  `json:"l1_head_timestamp"`
+
+	QuarantinedUntil *time.Time `json:"quarantined_until,omitempty"`
+
+	// Health is only reported if health checks are enabled
+	Health *HealthReport `json:"health,omitempty"`
+
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorTime *time.Time `json:"last_error_time,omitempty"`
+}
+
+// sequencerError is the last error seen while talking to a sequencer.
+type sequencerError struct {
+	err  error
+	time time.Time
+}
+
+// clusterSnapshot is the view of the heartbeat loop published for the status API,
+// so the status API never waits for a heartbeat which talks to the sequencers with s.mu held.
+type clusterSnapshot struct {
+	phase              Phase
+	primarySequencerID int
+	sequencerList      []string
+	degradedSince      time.Time
+	degradedRetries    int
+
+	// priorities, quarantined and executionEndpoints are by sequencer ID
+	priorities         []int
+	quarantined        []*time.Time
+	executionEndpoints []string
+
+	damping   DampingStatus
+	safeHead  *SafeHeadStatus
+	incidents []Incident
+}
+
+// publish takes a snapshot of the heartbeat loop for the status API, s.mu must be held
+func (s *Service) publish() {
+	snapshot := &clusterSnapshot{
+		phase:              s.Phase(),
+		primarySequencerID: s.primarySequencerID,
+		sequencerList:      s.sequencerList,
+		degradedSince:      s.degradedSince,
+		degradedRetries:    s.degradedRetries,
+		priorities:         make([]int, len(s.sequencerList)),
+		quarantined:        make([]*time.Time, len(s.sequencerList)),
+		executionEndpoints: make([]string, len(s.sequencerList)),
+		damping:            s.dampingStatus(),
+		safeHead:           s.safeHeadStatus(),
+		incidents:          append([]Incident(nil), s.incidents...),
+	}
+
+	for id, sequencer := range s.sequencerList {
+		snapshot.priorities[id] = s.priority(sequencer)
+		snapshot.executionEndpoints[id] = s.executionEndpoint(sequencer)
+
+		if until, ok := s.quarantinedUntil(sequencer); ok {
+			snapshot.quarantined[id] = &until
+		}
+	}
+
+	s.snapshot.Store(snapshot)
+}
+
+// Status polls all sequencers concurrently and reports their status,
+// along with the view of the heartbeat loop as of its last step.
+func (s *Service) Status(ctx context.Context) *ClusterStatus {
+	snapshot := s.snapshot.Load()
+	if snapshot == nil {
+		// Nothing is published before the service is initialized
+		s.mu.Lock()
+		s.publish()
+		s.mu.Unlock()
+
+		snapshot = s.snapshot.Load()
+	}
+
+	now := time.Now()
+
+	status := &ClusterStatus{
+		Phase:              snapshot.phase.String(),
+		PrimarySequencerID: snapshot.primarySequencerID,
+		Maintenance:        s.Maintenance(),
+		Damping:            snapshot.damping,
+		SafeHead:           snapshot.safeHead,
+		Incidents:          snapshot.incidents,
+		Sequencers:         make([]SequencerStatus, len(snapshot.sequencerList)),
+	}
+
+	// The cooldown could have ended since the snapshot was taken
+	if cooldownUntil := status.Damping.CooldownUntil; cooldownUntil != nil && !now.Before(*cooldownUntil) {
+		status.Damping.CooldownUntil = nil
+	}
+
+	if snapshot.phase == PhaseDegraded {
+		degradedSince := snapshot.degradedSince
+
+		status.DegradedSince = &degradedSince
+		status.DegradedRetries = snapshot.degradedRetries
+	}
+
+	var wg sync.WaitGroup
+
+	for id, sequencer := range snapshot.sequencerList {
+		wg.Add(1)
+
+		go func(id int, sequencer string) {
+			defer wg.Done()
+
+			status.Sequencers[id] = s.sequencerStatus(ctx, id, sequencer, snapshot.executionEndpoints[id])
+			status.Sequencers[id].Primary = id == snapshot.primarySequencerID
+			status.Sequencers[id].Priority = snapshot.priorities[id]
+
+			// The quarantine could have ended since the snapshot was taken
+			if until := snapshot.quarantined[id]; until != nil && now.Before(*until) {
+				status.Sequencers[id].QuarantinedUntil = until
+			}
+		}(id, sequencer)
+	}
+
+	wg.Wait()
+
+	return status
+}
+
+func (s *Service) sequencerStatus(ctx context.Context, id int, sequencer string, executionEndpoint string) SequencerStatus {
+	status := SequencerStatus{
+		ID:       id,
+		Endpoint: sequencer,
+
+		ExecutionEndpoint: executionEndpoint,
+	}
+
+	isActive, err := s.client.CheckSequencerActive(ctx, sequencer)
+	if err != nil {
+		s.recordError(sequencer, err)
+	}
+
+	status.Active = isActive
+
+	started := time.Now()
+
+	syncStatus, err := s.client.GetSyncStatus(ctx, sequencer)
+	latency := time.Since(started)
+
+	if err != nil {
+		s.recordError(sequencer, err)
+	} else {
+		status.Ready = syncStatus.IsReady()
+		status.UnsafeL2Number = syncStatus.UnsafeL2.Number
+		status.UnsafeL2Hash = syncStatus.UnsafeL2.Hash
+		status.UnsafeL2Timestamp = syncStatus.UnsafeL2.Timestamp
+		status.SafeL2Number = syncStatus.SafeL2.Number
+		status.FinalizedL2Number = syncStatus.FinalizedL2.Number
+		status.L1HeadTimestamp = syncStatus.HeadL1.Timestamp
+
+		if len(s.healthChecks) > 0 {
+			status.Health = s.evaluateHealth(ctx, &Probe{
+				Client:     s.client,
+				Sequencer:  sequencer,
+				SyncStatus: syncStatus,
+				Latency:    latency,
+
+				ExecutionEndpoint: executionEndpoint,
+			})
+		}
+	}
+
+	if lastError, ok := s.lastError(sequencer); ok {
+		status.LastError = lastError.err.Error()
+		status.LastErrorTime = &lastError.time
+	}
+
+	return status
+}
+
+// dampingStatus reports the flap damping state, s.mu must be held
+func (s *Service) dampingStatus() DampingStatus {
+	status := DampingStatus{
+		SwitchoversLastHour:   len(s.recentSwitchovers(time.Now())),
+		MaxSwitchoversPerHour: s.maxSwitchovers,
+	}
+
+	if !s.lastSwitchover.IsZero() {
+		lastSwitchover, cooldownUntil := s.lastSwitchover, s.cooldownUntil()
+
+		status.LastSwitchover = &lastSwitchover
+
+		if time.Now().Before(cooldownUntil) {
+			status.CooldownUntil = &cooldownUntil
+		}
+	}
+
+	return status
+}
+
+// recordError keeps the last error seen while talking to a sequencer.
+func (s *Service) recordError(sequencer string, err error) {
+	s.errorsMu.Lock()
+	defer s.errorsMu.Unlock()
+
+	if s.lastErrors == nil {
+		s.lastErrors = make(map[string]sequencerError)
+	}
+
+	s.lastErrors[sequencer] = sequencerError{
+		err:  err,
+		time: time.Now(),
+	}
+}
+
+func (s *Service) lastError(sequencer string) (sequencerError, bool) {
+	s.errorsMu.Lock()
+	defer s.errorsMu.Unlock()
+
+	lastError, ok := s.lastErrors[sequencer]
+
+	return lastError, ok
+}
@@ -0,0 +1,109 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 3)
+
+	// Condition 1: 1 is primary, 2 is not ready
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(i == 1)
+
+		ms.SetIsReady(i != 2)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+	}
+
+	s.setPrimary(1)
+
+	status := s.Status(context.Background())
+
+	if status.PrimarySequencerID != 1 {
+		t.Log("primary sequencer is incorrect", status.PrimarySequencerID)
+
+		t.Fail()
+	}
+
+	if len(status.Sequencers) != len(sequencers) {
+		t.Fatal("sequencer count is incorrect", len(status.Sequencers))
+	}
+
+	for i, sequencerStatus := range status.Sequencers {
+		if sequencerStatus.ID != i || sequencerStatus.Endpoint != s.sequencerList[i] {
+			t.Log("sequencer is incorrect", i)
+
+			t.Fail()
+		}
+
+		if sequencerStatus.Primary != (i == 1) || sequencerStatus.Active != (i == 1) {
+			t.Log("sequencer state is incorrect", i)
+
+			t.Fail()
+		}
+
+		if sequencerStatus.Ready != (i != 2) {
+			t.Log("sequencer ready state is incorrect", i)
+
+			t.Fail()
+		}
+
+		if sequencerStatus.UnsafeL2Hash != "unsafe-hash-1" {
+			t.Log("unsafe hash is incorrect", i, sequencerStatus.UnsafeL2Hash)
+
+			t.Fail()
+		}
+
+		if sequencerStatus.LastError != "" {
+			t.Log("should no error", i, sequencerStatus.LastError)
+
+			t.Fail()
+		}
+	}
+	// Condition 2: A heartbeat holds the lock while it talks to the sequencers
+	s.mu.Lock()
+
+	statuses := make(chan *ClusterStatus, 1)
+
+	go func() {
+		statuses <- s.Status(context.Background())
+	}()
+
+	// Situation 1: Should report the last published view without waiting for the heartbeat
+	select {
+	case status = <-statuses:
+		if status.PrimarySequencerID != 1 {
+			t.Log("primary sequencer is incorrect", status.PrimarySequencerID)
+
+			t.Fail()
+		}
+	case <-time.After(5 * time.Second):
+		t.Log("status should not wait for the heartbeat")
+
+		t.Fail()
+	}
+
+	// Situation 2: Should report the phase for the readiness probe without waiting for the heartbeat
+	phases := make(chan Phase, 1)
+
+	go func() {
+		phases <- s.Phase()
+	}()
+
+	select {
+	case <-phases:
+	case <-time.After(5 * time.Second):
+		t.Log("phase should not wait for the heartbeat")
+
+		t.Fail()
+	}
+
+	s.mu.Unlock()
+}
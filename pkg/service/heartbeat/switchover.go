@@ -0,0 +1,141 @@
+package heartbeat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNoPrimary      = errors.New("no primary sequencer is tracked")
+	ErrInvalidTarget  = errors.New("invalid target sequencer")
+	ErrAlreadyPrimary = errors.New("target sequencer is already primary")
+)
+
+// PrimarySequencerID returns the ID of the tracked primary sequencer, -1 if there is none.
+func (s *Service) PrimarySequencerID() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.primarySequencerID
+}
+
+// Switchover hands block production over from the primary sequencer to the target sequencer.
+// The target is started with the unsafe hash returned by stopping the primary, so no block is lost,
+// and the primary is restarted if the target fails to start.
+func (s *Service) Switchover(target int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The handover is never abandoned halfway, as the primary would be left stopped
+	return s.switchover(context.Background(), target, zap.L().With(zap.String("service", "heartbeat")))
+}
+
+// switchover hands block production over from the primary sequencer to the target sequencer, s.mu must be held
+func (s *Service) switchover(ctx context.Context, target int, log *zap.Logger) (int, error) {
+	log = log.With(zap.Int("target_id", target))
+
+	if s.primarySequencerID == -1 {
+		return -1, ErrNoPrimary
+	}
+
+	if target < 0 || target >= len(s.sequencerList) {
+		return s.primarySequencerID, ErrInvalidTarget
+	}
+
+	if target == s.primarySequencerID {
+		return target, ErrAlreadyPrimary
+	}
+
+	currentSequencerID := s.primarySequencerID
+
+	log.Info("Switching over primary sequencer", zap.Int("sequencer_id", currentSequencerID))
+
+	metrics.SwitchoverAttempts.Inc()
+
+	unsafeHash, err := s.client.DeactivateSequencer(ctx, s.sequencerList[currentSequencerID])
+	if err != nil {
+		metrics.SwitchoverFailures.Inc()
+
+		return currentSequencerID, fmt.Errorf("failed to deactivate primary sequencer %d: %w", currentSequencerID, err)
+	}
+
+	if _, err = s.activateSequencer(ctx, s.sequencerList[target], unsafeHash); err != nil {
+		metrics.SwitchoverFailures.Inc()
+
+		log.Error("Failed to activate target sequencer, restoring primary sequencer", zap.Error(err))
+
+		s.quarantine(s.sequencerList[target], err, log)
+
+		// Prefer the previous primary, then any other sequencer
+		candidates := slices.DeleteFunc(s.candidates(currentSequencerID), func(id int) bool { return id == currentSequencerID })
+
+		s.handOver(s.activateSequencers(ctx, append([]int{currentSequencerID}, candidates...), unsafeHash), log)
+
+		return s.primarySequencerID, fmt.Errorf("failed to activate sequencer %d: %w", target, err)
+	}
+
+	metrics.SwitchoverSuccesses.Inc()
+
+	s.handOver(target, log)
+
+	log.Info("New primary sequencer activated.", zap.Int("new_primary_id", target), zap.String("unsafe_hash", unsafeHash))
+
+	return target, nil
+}
+
+// Failover hands block production over from the primary sequencer to the next available sequencer.
+func (s *Service) Failover() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The handover is never abandoned halfway, as the primary would be left stopped
+	ctx := context.Background()
+
+	log := zap.L().With(zap.String("service", "heartbeat"))
+
+	if s.primarySequencerID == -1 {
+		return -1, ErrNoPrimary
+	}
+
+	currentSequencerID := s.primarySequencerID
+
+	log.Info("Failing over primary sequencer", zap.Int("sequencer_id", currentSequencerID))
+
+	metrics.SwitchoverAttempts.Inc()
+
+	unsafeHash, err := s.client.DeactivateSequencer(ctx, s.sequencerList[currentSequencerID])
+	if err != nil {
+		metrics.SwitchoverFailures.Inc()
+
+		return currentSequencerID, fmt.Errorf("failed to deactivate primary sequencer %d: %w", currentSequencerID, err)
+	}
+
+	// Start with the next sequencer by priority, the previous primary is the last resort
+	candidates := slices.DeleteFunc(s.candidates(currentSequencerID+1), func(id int) bool { return id == currentSequencerID })
+
+	newPrimaryID := s.activateSequencers(ctx, append(candidates, currentSequencerID), unsafeHash)
+
+	s.handOver(newPrimaryID, log)
+
+	switch newPrimaryID {
+	case -1:
+		metrics.SwitchoverFailures.Inc()
+
+		return -1, fmt.Errorf("failed to activate any sequencer")
+	case currentSequencerID:
+		metrics.SwitchoverFailures.Inc()
+
+		return currentSequencerID, fmt.Errorf("failed to activate any other sequencer")
+	}
+
+	metrics.SwitchoverSuccesses.Inc()
+
+	log.Info("New primary sequencer activated.", zap.Int("new_primary_id", newPrimaryID), zap.String("unsafe_hash", unsafeHash))
+
+	return newPrimaryID, nil
+}
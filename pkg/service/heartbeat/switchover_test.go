@@ -0,0 +1,127 @@
+package heartbeat
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSwitchover(t *testing.T) {
+	t.Parallel()
+
+	s, sequencers := newTestService(t, 3)
+
+	// Condition 1: 0 is primary, all is ready
+	for i, ms := range sequencers {
+		ms.SetIsWithAdmin(true)
+
+		ms.SetIsActivated(i == 0)
+
+		ms.SetIsReady(true)
+
+		ms.SetUnsafeHash("unsafe-hash-1")
+	}
+
+	s.setPrimary(0)
+
+	// Situation 1: Switch to 2, should hand over the unsafe hash of 0
+	sequencers[0].SetUnsafeHash("unsafe-hash-1.1")
+
+	// 2 has synced the unsafe block of 0 by gossip, so it can take over
+	sequencers[2].SetUnsafeHash("unsafe-hash-1.1")
+
+	primarySequencerID, err := s.Switchover(2)
+
+	if err != nil {
+		t.Log("should no error", err)
+
+		t.Fail()
+	}
+
+	if primarySequencerID != 2 || s.PrimarySequencerID() != 2 {
+		t.Log("activated wrong sequencer", primarySequencerID)
+
+		t.Fail()
+	}
+
+	for i, ms := range sequencers {
+		if ms.GetIsActivated() != (i == 2) {
+			t.Log("sequencer state is incorrect", i)
+
+			t.Fail()
+		}
+	}
+
+	if sequencers[2].GetUnsafeHash() != "unsafe-hash-1.1" {
+		t.Log("unsafe hash is not handed over", sequencers[2].GetUnsafeHash())
+
+		t.Fail()
+	}
+
+	// Situation 2: Switch to the primary itself or to an unknown sequencer, should keep 2
+	if _, err = s.Switchover(2); !errors.Is(err, ErrAlreadyPrimary) {
+		t.Log("should be already primary error", err)
+
+		t.Fail()
+	}
+
+	if _, err = s.Switchover(len(sequencers)); !errors.Is(err, ErrInvalidTarget) {
+		t.Log("should be invalid target error", err)
+
+		t.Fail()
+	}
+
+	if s.PrimarySequencerID() != 2 || !sequencers[2].GetIsActivated() {
+		t.Log("primary sequencer should not change")
+
+		t.Fail()
+	}
+
+	// Condition 2: 2 is primary, 1 is not ready
+	// Situation 1: Switch to 1, should restore 2
+	sequencers[1].SetIsReady(false)
+
+	primarySequencerID, err = s.Switchover(1)
+
+	if err == nil {
+		t.Log("should be error")
+
+		t.Fail()
+	}
+
+	if primarySequencerID != 2 || s.PrimarySequencerID() != 2 {
+		t.Log("activated wrong sequencer", primarySequencerID)
+
+		t.Fail()
+	}
+
+	for i, ms := range sequencers {
+		if ms.GetIsActivated() != (i == 2) {
+			t.Log("sequencer state is incorrect", i)
+
+			t.Fail()
+		}
+	}
+
+	// Situation 2: Fail over, should skip 1 and activate 0
+	primarySequencerID, err = s.Failover()
+
+	if err != nil {
+		t.Log("should no error", err)
+
+		t.Fail()
+	}
+
+	if primarySequencerID != 0 || s.PrimarySequencerID() != 0 {
+		t.Log("activated wrong sequencer", primarySequencerID)
+
+		t.Fail()
+	}
+
+	for i, ms := range sequencers {
+		if ms.GetIsActivated() != (i == 0) {
+			t.Log("sequencer state is incorrect", i)
+
+			t.Fail()
+		}
+	}
+}
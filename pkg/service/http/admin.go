@@ -0,0 +1,101 @@
+package http
+
+import (
+	"errors"
+	nethttp "net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rss3-network/vsl-reconcile/pkg/service/heartbeat"
+	"go.uber.org/zap"
+)
+
+// SwitchoverResponse is the response of POST /switchover and POST /failover.
+type SwitchoverResponse struct {
+	PrimarySequencerID int    `json:"primary_sequencer_id"`
+	Error              string `json:"error,omitempty"`
+}
+
+func (s *Service) postSwitchover(c echo.Context) error {
+	if err := s.requireLeader(); err != nil {
+		return err
+	}
+
+	target, err := strconv.Atoi(c.QueryParam("target"))
+	if err != nil {
+		return echo.NewHTTPError(nethttp.StatusBadRequest, "target must be a sequencer id")
+	}
+
+	primarySequencerID, err := s.heartbeat.Switchover(target)
+
+	return s.switchoverResponse(c, primarySequencerID, err)
+}
+
+func (s *Service) postFailover(c echo.Context) error {
+	if err := s.requireLeader(); err != nil {
+		return err
+	}
+
+	primarySequencerID, err := s.heartbeat.Failover()
+
+	return s.switchoverResponse(c, primarySequencerID, err)
+}
+
+// MaintenanceResponse is the response of POST /maintenance and DELETE /maintenance.
+type MaintenanceResponse struct {
+	Maintenance bool `json:"maintenance"`
+}
+
+func (s *Service) postMaintenance(c echo.Context) error {
+	return s.setMaintenance(c, true)
+}
+
+func (s *Service) deleteMaintenance(c echo.Context) error {
+	return s.setMaintenance(c, false)
+}
+
+func (s *Service) setMaintenance(c echo.Context, enabled bool) error {
+	if err := s.requireLeader(); err != nil {
+		return err
+	}
+
+	s.heartbeat.SetMaintenance(enabled)
+
+	// The StatefulSet annotation keeps maintenance mode on even if it is disabled here
+	return c.JSON(nethttp.StatusOK, MaintenanceResponse{
+		Maintenance: s.heartbeat.Maintenance(),
+	})
+}
+
+// requireLeader rejects requests which can only be served by the leader, as followers don't manage sequencers
+func (s *Service) requireLeader() error {
+	if s.elector.IsLeader() {
+		return nil
+	}
+
+	return echo.NewHTTPError(nethttp.StatusConflict, "not leader, current leader is "+s.elector.Leader())
+}
+
+func (s *Service) switchoverResponse(c echo.Context, primarySequencerID int, err error) error {
+	if err == nil {
+		return c.JSON(nethttp.StatusOK, SwitchoverResponse{
+			PrimarySequencerID: primarySequencerID,
+		})
+	}
+
+	zap.L().Error("switchover failed", zap.Error(err), zap.String("service", s.String()))
+
+	status := nethttp.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, heartbeat.ErrInvalidTarget):
+		status = nethttp.StatusBadRequest
+	case errors.Is(err, heartbeat.ErrAlreadyPrimary), errors.Is(err, heartbeat.ErrNoPrimary):
+		status = nethttp.StatusConflict
+	}
+
+	return c.JSON(status, SwitchoverResponse{
+		PrimarySequencerID: primarySequencerID,
+		Error:              err.Error(),
+	})
+}
@@ -2,17 +2,50 @@ package http
 
 import (
 	"context"
+	"crypto/subtle"
+	nethttp "net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rss3-network/vsl-reconcile/config"
 	"github.com/rss3-network/vsl-reconcile/internal/safe"
+	"github.com/rss3-network/vsl-reconcile/pkg/dryrun"
 	"github.com/rss3-network/vsl-reconcile/pkg/service"
+	"github.com/rss3-network/vsl-reconcile/pkg/service/heartbeat"
+	"go.uber.org/zap"
 )
 
 var _ service.Service = (*Service)(nil)
 
+// Elector reports the leader election state of this replica.
+type Elector interface {
+	Identity() string
+	Leader() string
+	IsLeader() bool
+}
+
+// Heartbeat manages the primary sequencer.
+type Heartbeat interface {
+	Status(ctx context.Context) *heartbeat.ClusterStatus
+	Phase() heartbeat.Phase
+	Switchover(target int) (int, error)
+	Failover() (int, error)
+	Maintenance() bool
+	SetMaintenance(enabled bool)
+}
+
 type Service struct {
-	server *echo.Echo
+	server    *echo.Echo
+	elector   Elector
+	heartbeat Heartbeat
+}
+
+func New(elector Elector, heartbeat Heartbeat) *Service {
+	return &Service{
+		elector:   elector,
+		heartbeat: heartbeat,
+	}
 }
 
 func (s *Service) Run(pool *safe.Pool) error {
@@ -26,13 +59,30 @@ func (s *Service) Run(pool *safe.Pool) error {
 	return nil
 }
 
-func (s *Service) Init(_ *config.Config) error {
+func (s *Service) Init(cfg *config.Config) error {
 	s.server = echo.New()
 	s.server.HideBanner = true
 	s.server.HidePort = true
 	s.server.GET("/", func(c echo.Context) error {
 		return c.String(200, "Hello, World!")
 	})
+	s.server.GET("/readyz", s.getReady)
+	s.server.GET("/leader", s.getLeader)
+	s.server.GET("/status", s.getStatus)
+	s.server.GET("/plan", s.getPlan)
+	s.server.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+
+	if cfg.APIToken == "" {
+		zap.L().Warn("admin api is disabled as api token is not provided", zap.String("service", s.String()))
+	} else {
+		admin := s.server.Group("", middleware.KeyAuth(func(key string, _ echo.Context) (bool, error) {
+			return subtle.ConstantTimeCompare([]byte(key), []byte(cfg.APIToken)) == 1, nil
+		}))
+		admin.POST("/switchover", s.postSwitchover)
+		admin.POST("/failover", s.postFailover)
+		admin.POST("/maintenance", s.postMaintenance)
+		admin.DELETE("/maintenance", s.deleteMaintenance)
+	}
 
 	return nil
 }
@@ -40,3 +90,62 @@ func (s *Service) Init(_ *config.Config) error {
 func (s *Service) String() string {
 	return "http"
 }
+
+// getReady fails until the leader has bootstrapped the primary sequencer,
+// followers never run the heartbeat loop and are always ready
+func (s *Service) getReady(c echo.Context) error {
+	if phase := s.heartbeat.Phase(); s.elector.IsLeader() && (phase == heartbeat.PhaseUnknown || phase == heartbeat.PhaseBootstrapping) {
+		return c.String(nethttp.StatusServiceUnavailable, phase.String())
+	}
+
+	return c.String(nethttp.StatusOK, "ok")
+}
+
+// LeaderResponse is the response of GET /leader.
+type LeaderResponse struct {
+	Identity string `json:"identity"`
+	Leader   string `json:"leader"`
+	IsLeader bool   `json:"is_leader"`
+}
+
+func (s *Service) getLeader(c echo.Context) error {
+	return c.JSON(nethttp.StatusOK, LeaderResponse{
+		Identity: s.elector.Identity(),
+		Leader:   s.elector.Leader(),
+		IsLeader: s.elector.IsLeader(),
+	})
+}
+
+// StatusResponse is the response of GET /status.
+type StatusResponse struct {
+	Leader string `json:"leader"`
+	*heartbeat.ClusterStatus
+}
+
+func (s *Service) getStatus(c echo.Context) error {
+	status := s.heartbeat.Status(c.Request().Context())
+
+	// The chain has no primary sequencer while degraded
+	code := nethttp.StatusOK
+	if status.Phase == heartbeat.PhaseDegraded.String() {
+		code = nethttp.StatusServiceUnavailable
+	}
+
+	return c.JSON(code, StatusResponse{
+		Leader:        s.elector.Leader(),
+		ClusterStatus: status,
+	})
+}
+
+// PlanResponse is the response of GET /plan.
+type PlanResponse struct {
+	DryRun  bool            `json:"dry_run"`
+	Actions []dryrun.Action `json:"actions"`
+}
+
+func (s *Service) getPlan(c echo.Context) error {
+	return c.JSON(nethttp.StatusOK, PlanResponse{
+		DryRun:  dryrun.Enabled(),
+		Actions: dryrun.Plan(),
+	})
+}
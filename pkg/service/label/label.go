@@ -3,16 +3,19 @@ package label
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/rss3-network/vsl-reconcile/config"
 	"github.com/rss3-network/vsl-reconcile/internal/rpc"
 	"github.com/rss3-network/vsl-reconcile/internal/safe"
+	"github.com/rss3-network/vsl-reconcile/pkg/discovery"
 	"github.com/rss3-network/vsl-reconcile/pkg/kube"
 	"github.com/rss3-network/vsl-reconcile/pkg/service"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -26,6 +29,15 @@ type Service struct {
 	name          string
 	namespace     string
 	checkInterval time.Duration
+	discovery     *discovery.StatefulSet
+	client        rpc.API
+}
+
+// New creates the label service, which checks the sequencers through client.
+func New(client rpc.API) *Service {
+	return &Service{
+		client: client,
+	}
 }
 
 func (s *Service) Run(pool *safe.Pool) error {
@@ -37,10 +49,22 @@ func (s *Service) Run(pool *safe.Pool) error {
 }
 
 func (s *Service) Init(cfg *config.Config) error {
+	if cfg.DiscoverySTS == "" {
+		return fmt.Errorf("pod labels are only available with statefulset discovery")
+	}
+
 	s.name = cfg.DiscoverySTS
 	s.namespace = cfg.DiscoveryNS
 	s.checkInterval = cfg.CheckInterval
 
+	clientset, err := kube.Client()
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubernetes client: %w", err)
+	}
+
+	// Sequencers are reached at the same endpoints as the heartbeat service uses
+	s.discovery = discovery.NewStatefulSet(clientset, cfg)
+
 	return nil
 }
 
@@ -69,35 +93,55 @@ func (s *Service) Loop(ctx context.Context) {
 	}
 
 	for {
-		pods, err := s.PodList(ctx)
-		if err != nil {
-			log.Error("failed to list pods", zap.Error(err))
+		s.labelPods(ctx, clientset, log)
+
+		select {
+		case <-ctx.Done():
 			return
+		case <-time.After(s.checkInterval):
+		}
+	}
+}
+
+// labelPods labels every pod with whether its sequencer is active, errors are retried on the next tick
+func (s *Service) labelPods(ctx context.Context, clientset *kubernetes.Clientset, log *zap.Logger) {
+	pods, err := s.PodList(ctx)
+	if err != nil {
+		log.Error("failed to list pods", zap.Error(err))
+		return
+	}
+
+	podEndpoints, err := s.discovery.PodEndpoints(ctx)
+	if err != nil {
+		log.Error("failed to discover pod endpoints", zap.Error(err))
+		return
+	}
+
+	for _, pod := range pods.Items {
+		pod := pod
+
+		url, ok := podEndpoints[pod.Name]
+		if !ok {
+			continue
+		}
+
+		isActive, err := s.client.CheckSequencerActive(ctx, url)
+
+		if err != nil {
+			log.Error("failed to check sequencer active", zap.Error(err))
+			continue
 		}
 
-		for _, pod := range pods.Items {
-			pod := pod
-			url := fmt.Sprintf("http://%s:9545", pod.Status.PodIP)
-			isActive, err := rpc.CheckSequencerActive(url)
-
-			if err != nil {
-				log.Error("failed to check sequencer active", zap.Error(err))
-				continue
-			}
-
-			if isActive {
-				err = kube.PatchPod(ctx, clientset, s.namespace, pod.Name, labelVSLActive, "true")
-				if err != nil {
-					log.Error("failed to patch pod", zap.Error(err), zap.String("pod", pod.Name))
-				}
-			} else {
-				err = kube.PatchPod(ctx, clientset, s.namespace, pod.Name, labelVSLActive, "false")
-				if err != nil {
-					log.Error("failed to patch pod", zap.Error(err), zap.String("pod", pod.Name))
-				}
-			}
+		value := strconv.FormatBool(isActive)
+
+		// Skip pods already labelled, which also keeps the dry-run plan free of no-op patches
+		if pod.Labels[labelVSLActive] == value {
+			continue
 		}
 
-		time.Sleep(s.checkInterval)
+		err = kube.PatchPod(ctx, clientset, s.namespace, pod.Name, labelVSLActive, value)
+		if err != nil {
+			log.Error("failed to patch pod", zap.Error(err), zap.String("pod", pod.Name))
+		}
 	}
 }
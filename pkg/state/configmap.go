@@ -0,0 +1,62 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/kube"
+	"k8s.io/client-go/kubernetes"
+)
+
+// configMapKey is the key of the ConfigMap holding the state
+const configMapKey = "state.json"
+
+var _ Store = (*ConfigMap)(nil)
+
+// ConfigMap keeps the state in a ConfigMap, so it is shared by all reconcile replicas.
+type ConfigMap struct {
+	clientset *kubernetes.Clientset
+	namespace string
+	name      string
+}
+
+func NewConfigMap(clientset *kubernetes.Clientset, namespace, name string) *ConfigMap {
+	return &ConfigMap{
+		clientset: clientset,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+func (c *ConfigMap) Load(ctx context.Context) (*State, error) {
+	data, err := kube.ConfigMapData(ctx, c.clientset, c.namespace, c.name, configMapKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if data == "" {
+		return nil, nil
+	}
+
+	var state State
+
+	if err = json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state configmap %s/%s: %w", c.namespace, c.name, err)
+	}
+
+	return &state, nil
+}
+
+func (c *ConfigMap) Save(ctx context.Context, state *State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return kube.SetConfigMapData(ctx, c.clientset, c.namespace, c.name, configMapKey, string(data))
+}
+
+func (c *ConfigMap) String() string {
+	return "configmap"
+}
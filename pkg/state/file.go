@@ -0,0 +1,80 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rss3-network/vsl-reconcile/pkg/dryrun"
+)
+
+var _ Store = (*File)(nil)
+
+// File keeps the state in a JSON file, e.g. on the data volume.
+type File struct {
+	path string
+}
+
+func NewFile(path string) *File {
+	return &File{
+		path: path,
+	}
+}
+
+func (f *File) Load(_ context.Context) (*State, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var state State
+
+	if err = json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", f.path, err)
+	}
+
+	return &state, nil
+}
+
+// Save writes the state to a temporary file first, so a crash never leaves a partial state behind.
+// In dry-run mode the write is only recorded in the plan.
+func (f *File) Save(_ context.Context, state *State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if dryrun.Enabled() {
+		dryrun.Record(dryrun.Action{
+			Kind:   dryrun.KindFile,
+			Target: f.path,
+			Method: "write",
+			Params: []string{string(data)},
+		})
+
+		return nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := f.path + ".tmp"
+
+	if err = os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, f.path)
+}
+
+func (f *File) String() string {
+	return "file"
+}
@@ -0,0 +1,45 @@
+package state
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	store := NewFile(filepath.Join(t.TempDir(), "data", "state.json"))
+
+	// Situation 1: Nothing is saved yet
+	state, err := store.Load(context.Background())
+
+	if err != nil || state != nil {
+		t.Log("state should be empty", state, err)
+
+		t.Fail()
+	}
+
+	// Situation 2: Load returns the saved state
+	saved := &State{
+		Primary:        "http://sequencer-1:9545",
+		BlockHeight:    2730980,
+		BlockTime:      time.Unix(1714122348, 0).UTC(),
+		LastSwitchover: time.Unix(1714120000, 0).UTC(),
+	}
+
+	if err = store.Save(context.Background(), saved); err != nil {
+		t.Log("failed to save state", err)
+
+		t.Fail()
+	}
+
+	state, err = store.Load(context.Background())
+
+	if err != nil || state == nil || *state != *saved {
+		t.Log("state mismatch", state, err)
+
+		t.Fail()
+	}
+}
@@ -0,0 +1,50 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rss3-network/vsl-reconcile/config"
+	"github.com/rss3-network/vsl-reconcile/pkg/kube"
+)
+
+// State is the reconcile state which is kept across restarts.
+type State struct {
+	// Primary is the endpoint of the primary sequencer
+	Primary string `json:"primary"`
+
+	// BlockHeight is the last block height of the primary sequencer, produced at BlockTime
+	BlockHeight int64     `json:"block_height"`
+	BlockTime   time.Time `json:"block_time"`
+
+	LastSwitchover time.Time `json:"last_switchover"`
+
+	// Maintenance is whether automatic failover was paused through the admin API, so a new leader keeps it paused
+	Maintenance bool `json:"maintenance"`
+}
+
+// Store loads and saves the reconcile state.
+type Store interface {
+	// Load returns nil if no state has been saved yet
+	Load(ctx context.Context) (*State, error)
+	Save(ctx context.Context, state *State) error
+	String() string
+}
+
+// New creates the store configured by cfg, nil if the state is not persisted.
+func New(cfg *config.Config) (Store, error) {
+	switch cfg.StateStore {
+	case config.StateStoreFile:
+		return NewFile(cfg.StateFile), nil
+	case config.StateStoreConfigMap:
+		clientset, err := kube.Client()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize kubernetes client: %w", err)
+		}
+
+		return NewConfigMap(clientset, cfg.DiscoveryNS, cfg.StateConfigMap), nil
+	default:
+		return nil, nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -37,14 +38,25 @@ type HeadL1Status struct {
 }
 
 type UnsafeL2Status struct {
+	Hash      string `json:"hash"`
+	Number    int64  `json:"number"`
+	Timestamp int64  `json:"timestamp"` // For check if sequencer is producing blocks
+}
+
+type L2BlockStatus struct {
 	Hash   string `json:"hash"`
 	Number int64  `json:"number"`
-	// Timestamp int64 `json:"timestamp"` // Not for isReady status reference
 }
 
 type OPSyncStatus struct { // Ignore irrelevant fields
-	HeadL1   HeadL1Status   `json:"head_l1"`
-	UnsafeL2 UnsafeL2Status `json:"unsafe_l2"`
+	HeadL1      HeadL1Status   `json:"head_l1"`
+	UnsafeL2    UnsafeL2Status `json:"unsafe_l2"`
+	SafeL2      L2BlockStatus  `json:"safe_l2"`
+	FinalizedL2 L2BlockStatus  `json:"finalized_l2"`
+}
+
+type PeerDump struct { // Ignore irrelevant fields
+	TotalConnected int `json:"totalConnected"`
 }
 
 /********************* Initialize mock sequencer *********************/
@@ -53,11 +65,27 @@ type MockSequencer struct {
 	listener net.Listener
 	server   http.Server
 
+	// mu guards the state below, which is changed by tests while requests are served
+	mu sync.Mutex
+
 	isWithAdmin bool // Can be activated
 	isActivated bool // Is now activated
 	isReady     bool // Is sync with mainnet
 
-	unsafeHash string // Unsafe L2 block hash
+	unsafeHash      string // Unsafe L2 block hash
+	unsafeNumber    int64  // Unsafe L2 block number
+	unsafeTimestamp int64  // Unsafe L2 block timestamp
+
+	safeNumber      int64 // Safe L2 block number, 0 if not derived yet
+	finalizedNumber int64 // Finalized L2 block number, 0 if not finalized yet
+
+	isWithPeers bool // Serves opp2p_peers
+	peerCount   int  // Connected peers
+
+	isWithExecution    bool  // Serves eth_blockNumber, eth_syncing and net_peerCount like op-geth
+	executionNumber    int64 // Latest execution block number
+	isSyncing          bool  // Execution client is syncing
+	executionPeerCount int   // Execution client peers
 }
 
 func NewMockSequencer() (*MockSequencer, string, error) {
@@ -105,6 +133,9 @@ func noSuchMethod(method string) string {
 }
 
 func (ms *MockSequencer) handleJSONRPC(w http.ResponseWriter, req *http.Request) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	// Parse request
 	var reqBody JSONRPCRequestData
 
@@ -192,7 +223,9 @@ func (ms *MockSequencer) handleJSONRPC(w http.ResponseWriter, req *http.Request)
 				Timestamp: 0,
 			},
 			UnsafeL2: UnsafeL2Status{
-				Hash: ms.unsafeHash,
+				Hash:      ms.unsafeHash,
+				Number:    ms.unsafeNumber,
+				Timestamp: ms.unsafeTimestamp,
 			},
 		}
 
@@ -200,6 +233,63 @@ func (ms *MockSequencer) handleJSONRPC(w http.ResponseWriter, req *http.Request)
 			resBody.Result.HeadL1.Timestamp = time.Now().Unix()
 		}
 
+		if ms.safeNumber > 0 {
+			resBody.Result.SafeL2 = L2BlockStatus{Hash: fmt.Sprintf("safe-hash-%d", ms.safeNumber), Number: ms.safeNumber}
+		}
+
+		if ms.finalizedNumber > 0 {
+			resBody.Result.FinalizedL2 = L2BlockStatus{Hash: fmt.Sprintf("finalized-hash-%d", ms.finalizedNumber), Number: ms.finalizedNumber}
+		}
+
+		resBodyBytes, _ = json.Marshal(&resBody)
+
+	case "opp2p_peers":
+		resBody := JSONRPCResponse[PeerDump]{
+			Version: reqBody.Version,
+			ID:      reqBody.ID,
+		}
+
+		if ms.isWithPeers {
+			resBody.Result = &PeerDump{TotalConnected: ms.peerCount}
+		} else {
+			resBody.Error = &JSONRPCResponseError{
+				-32601,
+				noSuchMethod("opp2p_peers"),
+			}
+		}
+
+		resBodyBytes, _ = json.Marshal(&resBody)
+
+	case "eth_blockNumber", "eth_syncing", "net_peerCount":
+		resBody := JSONRPCResponse[any]{
+			Version: reqBody.Version,
+			ID:      reqBody.ID,
+		}
+
+		var result any
+
+		switch reqBody.Method {
+		case "eth_blockNumber":
+			result = fmt.Sprintf("0x%x", ms.executionNumber)
+		case "eth_syncing":
+			result = false
+
+			if ms.isSyncing {
+				result = map[string]string{"currentBlock": fmt.Sprintf("0x%x", ms.executionNumber)}
+			}
+		case "net_peerCount":
+			result = fmt.Sprintf("0x%x", ms.executionPeerCount)
+		}
+
+		if ms.isWithExecution {
+			resBody.Result = &result
+		} else {
+			resBody.Error = &JSONRPCResponseError{
+				-32601,
+				noSuchMethod(reqBody.Method),
+			}
+		}
+
 		resBodyBytes, _ = json.Marshal(&resBody)
 
 	default:
@@ -225,33 +315,109 @@ func (ms *MockSequencer) Close() {
 /********************* Manage mock sequencer status *********************/
 
 func (ms *MockSequencer) SetIsWithAdmin(isWithAdmin bool) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	ms.isWithAdmin = isWithAdmin
 }
 
 func (ms *MockSequencer) GetIsWithAdmin() bool {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	return ms.isWithAdmin
 }
 
 func (ms *MockSequencer) SetIsActivated(isActivated bool) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	ms.isActivated = isActivated
 }
 
 func (ms *MockSequencer) GetIsActivated() bool {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	return ms.isActivated
 }
 
 func (ms *MockSequencer) SetIsReady(isReady bool) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	ms.isReady = isReady
 }
 
 func (ms *MockSequencer) GetIsReady() bool {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	return ms.isReady
 }
 
 func (ms *MockSequencer) SetUnsafeHash(hash string) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	ms.unsafeHash = hash
 }
 
 func (ms *MockSequencer) GetUnsafeHash() string {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	return ms.unsafeHash
 }
+
+func (ms *MockSequencer) SetUnsafeBlock(number int64, timestamp time.Time) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.unsafeNumber = number
+	ms.unsafeTimestamp = timestamp.Unix()
+}
+
+func (ms *MockSequencer) SetSafeBlock(number int64) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.safeNumber = number
+}
+
+func (ms *MockSequencer) SetFinalizedBlock(number int64) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.finalizedNumber = number
+}
+
+func (ms *MockSequencer) SetPeerCount(peerCount int) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.isWithPeers = true
+	ms.peerCount = peerCount
+}
+
+func (ms *MockSequencer) SetExecutionBlock(number int64) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.isWithExecution = true
+	ms.executionNumber = number
+}
+
+func (ms *MockSequencer) SetIsSyncing(isSyncing bool) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.isSyncing = isSyncing
+}
+
+func (ms *MockSequencer) SetExecutionPeerCount(peerCount int) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.executionPeerCount = peerCount
+}